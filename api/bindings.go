@@ -0,0 +1,144 @@
+package api
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/nats-io/nats.go"
+	"github.com/sandrolain/rules/engine"
+	"google.golang.org/protobuf/proto"
+)
+
+func (h *NatsHandler) handleSetInputBinding(msg *nats.Msg) {
+	var req SetInputBindingRequest
+	if err := proto.Unmarshal(msg.Data, &req); err != nil {
+		slog.Error("Error unmarshalling SetInputBinding request", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		slog.Error("Error validating SetInputBinding request", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	binding := convertProtoToModelBinding(req.Binding)
+	err := h.checkBindingSubject(binding.Subject)
+	if err == nil {
+		if h.opts.SyncMode == SyncWatch {
+			// The binding is set by the store watcher, here it is only checked
+			err = h.ruleEngine.CheckBinding(binding)
+		} else {
+			err = h.ruleEngine.SetBinding(binding)
+		}
+	}
+	if err != nil {
+		slog.Error("Error setting input binding", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	if err := h.store.SaveBinding(req.Binding); err != nil {
+		slog.Error("Error storing input binding", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	resp := &SetInputBindingResponse{Success: true}
+	if err := h.replyWithProto(msg, resp); err != nil {
+		slog.Error("Error sending response", "error", err)
+	}
+}
+
+// checkBindingSubject verifies that inputs published on a subject reach the
+// engine, which receives only the input subject and the subjects under it.
+func (h *NatsHandler) checkBindingSubject(subject string) error {
+	if h.opts.InputSubject == "" || subject == h.opts.InputSubject || strings.HasPrefix(subject, h.opts.InputSubject+".") {
+		return nil
+	}
+	return fmt.Errorf("binding subject %s is not under the input subject %s", subject, h.opts.InputSubject)
+}
+
+func (h *NatsHandler) handleListInputBindings(msg *nats.Msg) {
+	bindings := h.ruleEngine.Bindings()
+	resp := &ListInputBindingsResponse{
+		Bindings: make([]*InputBinding, len(bindings)),
+	}
+	for i, b := range bindings {
+		resp.Bindings[i] = convertModelToProtoBinding(b)
+	}
+	if err := h.replyWithProto(msg, resp); err != nil {
+		slog.Error("Error sending response", "error", err)
+	}
+}
+
+func (h *NatsHandler) handleDeleteInputBinding(msg *nats.Msg) {
+	var req DeleteInputBindingRequest
+	if err := proto.Unmarshal(msg.Data, &req); err != nil {
+		slog.Error("Error unmarshalling DeleteInputBinding request", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		slog.Error("Error validating DeleteInputBinding request", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	var err error
+	if h.opts.SyncMode == SyncWatch {
+		// The binding is removed by the store watcher, here it is only checked
+		_, err = h.ruleEngine.GetBinding(req.Subject)
+	} else {
+		err = h.ruleEngine.RemoveBinding(req.Subject)
+	}
+	if err != nil {
+		slog.Error("Error deleting input binding", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	if err := h.store.DeleteBinding(req.Subject); err != nil {
+		slog.Error("Error deleting stored input binding", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	resp := &DeleteInputBindingResponse{Success: true}
+	if err := h.replyWithProto(msg, resp); err != nil {
+		slog.Error("Error sending response", "error", err)
+	}
+}
+
+func convertProtoToModelBinding(b *InputBinding) engine.Binding {
+	return engine.Binding{
+		Subject:     b.Subject,
+		MessageType: b.MessageType,
+	}
+}
+
+func convertModelToProtoBinding(b engine.Binding) *InputBinding {
+	return &InputBinding{
+		Subject:     b.Subject,
+		MessageType: b.MessageType,
+	}
+}
@@ -0,0 +1,142 @@
+package api
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/sandrolain/rules/engine"
+	"google.golang.org/protobuf/proto"
+)
+
+func (h *NatsHandler) handleSetCalendar(msg *nats.Msg) {
+	var req SetCalendarRequest
+	if err := proto.Unmarshal(msg.Data, &req); err != nil {
+		slog.Error("Error unmarshalling SetCalendar request", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		slog.Error("Error validating SetCalendar request", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	calendar := convertProtoToModelCalendar(req.Calendar)
+	var err error
+	if h.opts.SyncMode == SyncWatch {
+		// The calendar is set by the store watcher, here it is only checked
+		err = engine.CheckCalendar(calendar)
+	} else {
+		err = h.ruleEngine.SetCalendar(calendar)
+	}
+	if err != nil {
+		slog.Error("Error setting calendar", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	if err := h.store.SaveCalendar(req.Calendar); err != nil {
+		slog.Error("Error storing calendar", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	resp := &SetCalendarResponse{Success: true}
+	if err := h.replyWithProto(msg, resp); err != nil {
+		slog.Error("Error sending response", "error", err)
+	}
+}
+
+func (h *NatsHandler) handleListCalendars(msg *nats.Msg) {
+	calendars := h.ruleEngine.Calendars()
+	resp := &ListCalendarsResponse{
+		Calendars: make([]*Calendar, len(calendars)),
+	}
+	for i, c := range calendars {
+		resp.Calendars[i] = convertModelToProtoCalendar(c)
+	}
+	if err := h.replyWithProto(msg, resp); err != nil {
+		slog.Error("Error sending response", "error", err)
+	}
+}
+
+func (h *NatsHandler) handleDeleteCalendar(msg *nats.Msg) {
+	var req DeleteCalendarRequest
+	if err := proto.Unmarshal(msg.Data, &req); err != nil {
+		slog.Error("Error unmarshalling DeleteCalendar request", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		slog.Error("Error validating DeleteCalendar request", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	var err error
+	if h.opts.SyncMode == SyncWatch {
+		// The calendar is removed by the store watcher, here it is only checked
+		_, err = h.ruleEngine.GetCalendar(req.Name)
+	} else {
+		err = h.ruleEngine.RemoveCalendar(req.Name)
+	}
+	if err != nil {
+		slog.Error("Error deleting calendar", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	if err := h.store.DeleteCalendar(req.Name); err != nil {
+		slog.Error("Error deleting stored calendar", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	resp := &DeleteCalendarResponse{Success: true}
+	if err := h.replyWithProto(msg, resp); err != nil {
+		slog.Error("Error sending response", "error", err)
+	}
+}
+
+func convertProtoToModelCalendar(c *Calendar) engine.Calendar {
+	weekend := make([]time.Weekday, len(c.WeekendDays))
+	for i, d := range c.WeekendDays {
+		weekend[i] = time.Weekday(d)
+	}
+	return engine.Calendar{
+		Name:        c.Name,
+		Holidays:    c.Holidays,
+		WeekendDays: weekend,
+	}
+}
+
+func convertModelToProtoCalendar(c engine.Calendar) *Calendar {
+	weekend := make([]int32, len(c.WeekendDays))
+	for i, d := range c.WeekendDays {
+		weekend[i] = int32(d)
+	}
+	return &Calendar{
+		Name:        c.Name,
+		Holidays:    c.Holidays,
+		WeekendDays: weekend,
+	}
+}
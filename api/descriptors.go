@@ -0,0 +1,120 @@
+package api
+
+import (
+	"log/slog"
+
+	"github.com/nats-io/nats.go"
+	"github.com/sandrolain/rules/engine"
+	"google.golang.org/protobuf/proto"
+)
+
+func (h *NatsHandler) handleSetDescriptorSet(msg *nats.Msg) {
+	var req SetDescriptorSetRequest
+	if err := proto.Unmarshal(msg.Data, &req); err != nil {
+		slog.Error("Error unmarshalling SetDescriptorSet request", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		slog.Error("Error validating SetDescriptorSet request", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	var set engine.DescriptorSet
+	var err error
+	if h.opts.SyncMode == SyncWatch {
+		// The set is registered by the store watcher, here it is only checked
+		set, err = engine.CheckDescriptorSet(req.Name, req.DescriptorSet)
+	} else {
+		set, err = h.ruleEngine.RegisterDescriptorSet(req.Name, req.DescriptorSet)
+	}
+	if err != nil {
+		slog.Error("Error registering descriptor set", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	if err := h.store.SaveDescriptorSet(req.Name, req.DescriptorSet); err != nil {
+		slog.Error("Error storing descriptor set", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	resp := &SetDescriptorSetResponse{Success: true, MessageTypes: set.MessageTypes}
+	if err := h.replyWithProto(msg, resp); err != nil {
+		slog.Error("Error sending response", "error", err)
+	}
+}
+
+func (h *NatsHandler) handleListDescriptorSets(msg *nats.Msg) {
+	sets := h.ruleEngine.DescriptorSets()
+	resp := &ListDescriptorSetsResponse{
+		DescriptorSets: make([]*DescriptorSet, len(sets)),
+	}
+	for i, set := range sets {
+		resp.DescriptorSets[i] = &DescriptorSet{
+			Name:         set.Name,
+			MessageTypes: set.MessageTypes,
+		}
+	}
+	if err := h.replyWithProto(msg, resp); err != nil {
+		slog.Error("Error sending response", "error", err)
+	}
+}
+
+func (h *NatsHandler) handleDeleteDescriptorSet(msg *nats.Msg) {
+	var req DeleteDescriptorSetRequest
+	if err := proto.Unmarshal(msg.Data, &req); err != nil {
+		slog.Error("Error unmarshalling DeleteDescriptorSet request", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		slog.Error("Error validating DeleteDescriptorSet request", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	var err error
+	if h.opts.SyncMode == SyncWatch {
+		// The set is removed by the store watcher, here it is only checked
+		_, err = h.ruleEngine.GetDescriptorSet(req.Name)
+	} else {
+		err = h.ruleEngine.RemoveDescriptorSet(req.Name)
+	}
+	if err != nil {
+		slog.Error("Error deleting descriptor set", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	if err := h.store.DeleteDescriptorSet(req.Name); err != nil {
+		slog.Error("Error deleting stored descriptor set", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	resp := &DeleteDescriptorSetResponse{Success: true}
+	if err := h.replyWithProto(msg, resp); err != nil {
+		slog.Error("Error sending response", "error", err)
+	}
+}
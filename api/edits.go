@@ -0,0 +1,150 @@
+package api
+
+import (
+	"log/slog"
+
+	"github.com/nats-io/nats.go"
+	"github.com/sandrolain/rules/engine"
+	"github.com/sandrolain/rules/models"
+	"google.golang.org/protobuf/proto"
+)
+
+// editRequest is implemented by all the requests that edit a single rule or
+// threshold of a policy.
+type editRequest interface {
+	proto.Message
+	GetPolicyId() string
+	GetExpectedRevision() int64
+	GetAuthor() string
+	GetNote() string
+}
+
+func (h *NatsHandler) handleEditRequests() error {
+	handlers := map[string]nats.MsgHandler{
+		AddRule: func(msg *nats.Msg) {
+			var req AddRuleRequest
+			h.handleEdit(msg, &req, "AddRule", func(p *models.Policy) error {
+				position := -1
+				if req.Position != nil {
+					position = int(*req.Position)
+				}
+				rule, err := convertProtoToModelRule(req.Rule)
+				if err != nil {
+					return err
+				}
+				return p.InsertRule(rule, position)
+			})
+		},
+		UpdateRule: func(msg *nats.Msg) {
+			var req UpdateRuleRequest
+			h.handleEdit(msg, &req, "UpdateRule", func(p *models.Policy) error {
+				rule, err := convertProtoToModelRule(req.Rule)
+				if err != nil {
+					return err
+				}
+				return p.UpdateRule(req.RuleName, rule)
+			})
+		},
+		RemoveRule: func(msg *nats.Msg) {
+			var req RemoveRuleRequest
+			h.handleEdit(msg, &req, "RemoveRule", func(p *models.Policy) error {
+				return p.RemoveRule(req.RuleName)
+			})
+		},
+		ReorderRules: func(msg *nats.Msg) {
+			var req ReorderRulesRequest
+			h.handleEdit(msg, &req, "ReorderRules", func(p *models.Policy) error {
+				return p.ReorderRules(req.RuleNames)
+			})
+		},
+		EnableRule: func(msg *nats.Msg) {
+			var req SetRuleStateRequest
+			h.handleEdit(msg, &req, "EnableRule", func(p *models.Policy) error {
+				return p.SetRuleEnabled(req.RuleName, true)
+			})
+		},
+		DisableRule: func(msg *nats.Msg) {
+			var req SetRuleStateRequest
+			h.handleEdit(msg, &req, "DisableRule", func(p *models.Policy) error {
+				return p.SetRuleEnabled(req.RuleName, false)
+			})
+		},
+		SetThreshold: func(msg *nats.Msg) {
+			var req SetThresholdRequest
+			h.handleEdit(msg, &req, "SetThreshold", func(p *models.Policy) error {
+				threshold, err := convertProtoToModelThreshold(req.Threshold)
+				if err != nil {
+					return err
+				}
+				return p.SetThreshold(threshold)
+			})
+		},
+		RemoveThreshold: func(msg *nats.Msg) {
+			var req RemoveThresholdRequest
+			h.handleEdit(msg, &req, "RemoveThreshold", func(p *models.Policy) error {
+				return p.RemoveThreshold(req.ThresholdId)
+			})
+		},
+		EnableThreshold: func(msg *nats.Msg) {
+			var req SetThresholdStateRequest
+			h.handleEdit(msg, &req, "EnableThreshold", func(p *models.Policy) error {
+				return p.SetThresholdEnabled(req.ThresholdId, true)
+			})
+		},
+		DisableThreshold: func(msg *nats.Msg) {
+			var req SetThresholdStateRequest
+			h.handleEdit(msg, &req, "DisableThreshold", func(p *models.Policy) error {
+				return p.SetThresholdEnabled(req.ThresholdId, false)
+			})
+		},
+	}
+
+	for subject, handler := range handlers {
+		if err := h.subscribe(subject, handler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handleEdit decodes and validates an edit request, applies the edit to the
+// current definition of the policy and replies with the new revision. The
+// edit closure reads the request after it has been decoded.
+func (h *NatsHandler) handleEdit(msg *nats.Msg, req editRequest, name string, edit func(*models.Policy) error) {
+	if err := proto.Unmarshal(msg.Data, req); err != nil {
+		slog.Error("Error unmarshalling "+name+" request", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		slog.Error("Error validating "+name+" request", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	opts := engine.WriteOptions{
+		Author:           req.GetAuthor(),
+		Note:             req.GetNote(),
+		ExpectedRevision: req.GetExpectedRevision(),
+	}
+	rev, err := h.writeRevision(func() (models.PolicyRevision, error) {
+		return h.ruleEngine.PrepareModify(req.GetPolicyId(), opts, edit)
+	})
+	if err != nil {
+		slog.Error("Error editing policy", "operation", name, "policy_id", req.GetPolicyId(), "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	resp := &EditPolicyResponse{Success: true, Revision: rev.Revision}
+	if err := h.replyWithProto(msg, resp); err != nil {
+		slog.Error("Error sending response", "error", err)
+	}
+}
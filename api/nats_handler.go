@@ -1,31 +1,102 @@
 package api
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"log/slog"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/bufbuild/protovalidate-go"
 	"github.com/nats-io/nats.go"
+	rcel "github.com/sandrolain/rules/cel"
 	"github.com/sandrolain/rules/engine"
-	"github.com/sandrolain/rules/models" // Add this import
+	"github.com/sandrolain/rules/models"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 const (
-	SubjectPrefix = "rules.engine"
-	SetPolicy     = SubjectPrefix + ".policy.set"
-	ListPolicies  = SubjectPrefix + ".policy.list"
-	GetPolicy     = SubjectPrefix + ".policy.get"
-	DeletePolicy  = SubjectPrefix + ".policy.delete"
+	SubjectPrefix  = "rules.engine"
+	SetPolicy      = SubjectPrefix + ".policy.set"
+	ListPolicies   = SubjectPrefix + ".policy.list"
+	GetPolicy      = SubjectPrefix + ".policy.get"
+	DeletePolicy   = SubjectPrefix + ".policy.delete"
+	ValidatePolicy = SubjectPrefix + ".policy.validate"
+	PolicySetHash  = SubjectPrefix + ".policy.hash"
+	ListRevisions  = SubjectPrefix + ".policy.revision.list"
+	GetRevision    = SubjectPrefix + ".policy.revision.get"
+	RollbackPolicy = SubjectPrefix + ".policy.rollback"
+
+	AddRule          = SubjectPrefix + ".policy.rule.add"
+	UpdateRule       = SubjectPrefix + ".policy.rule.update"
+	RemoveRule       = SubjectPrefix + ".policy.rule.remove"
+	ReorderRules     = SubjectPrefix + ".policy.rule.reorder"
+	EnableRule       = SubjectPrefix + ".policy.rule.enable"
+	DisableRule      = SubjectPrefix + ".policy.rule.disable"
+	SetThreshold     = SubjectPrefix + ".policy.threshold.set"
+	RemoveThreshold  = SubjectPrefix + ".policy.threshold.remove"
+	EnableThreshold  = SubjectPrefix + ".policy.threshold.enable"
+	DisableThreshold = SubjectPrefix + ".policy.threshold.disable"
+
+	SetDescriptorSet    = SubjectPrefix + ".descriptor.set"
+	ListDescriptorSets  = SubjectPrefix + ".descriptor.list"
+	DeleteDescriptorSet = SubjectPrefix + ".descriptor.delete"
+	SetInputBinding     = SubjectPrefix + ".binding.set"
+	ListInputBindings   = SubjectPrefix + ".binding.list"
+	DeleteInputBinding  = SubjectPrefix + ".binding.delete"
+	SetCalendar         = SubjectPrefix + ".calendar.set"
+	ListCalendars       = SubjectPrefix + ".calendar.list"
+	DeleteCalendar      = SubjectPrefix + ".calendar.delete"
+
+	// QueueGroup is used in watch mode, so that each management request is
+	// handled by a single instance of the engine
+	QueueGroup = SubjectPrefix
+
+	// ExplainHeader, set to "true" on an input message, makes the engine
+	// explain the evaluation of every policy in the output
+	ExplainHeader = "Rules-Explain"
 )
 
+// SyncMode selects how the policies are kept in sync between the instances
+// of the engine.
+type SyncMode string
+
+const (
+	// SyncLocal applies the changes to the local engine and persists them;
+	// every instance handles every management request on its own.
+	SyncLocal SyncMode = "local"
+	// SyncWatch writes the changes only to the key-value store; every
+	// instance applies them to its engine by watching the store. There is no
+	// read-your-writes guarantee: a request that follows a write, even on
+	// the same instance, may see the engine before the watcher applied it.
+	SyncWatch SyncMode = "watch"
+)
+
+type HandlerOptions struct {
+	InstanceID string
+	SyncMode   SyncMode
+	// InputSubject, if set, is the subject the input bindings must be under
+	InputSubject string
+}
+
 type NatsHandler struct {
 	nc         *nats.Conn
 	ruleEngine *engine.RuleEngine
+	store      *PolicyStore
+	opts       HandlerOptions
 	validator  *protovalidate.Validator
+	// writeMu serializes the policy writes in local mode, so that a revision
+	// is still the next one when it is committed after being stored
+	writeMu sync.Mutex
 }
 
-func NewNatsHandler(nc *nats.Conn, ruleEngine *engine.RuleEngine) (*NatsHandler, error) {
+func NewNatsHandler(nc *nats.Conn, ruleEngine *engine.RuleEngine, store *PolicyStore, opts HandlerOptions) (*NatsHandler, error) {
 	validator, err := protovalidate.New()
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize validator: %v", err)
@@ -34,26 +105,84 @@ func NewNatsHandler(nc *nats.Conn, ruleEngine *engine.RuleEngine) (*NatsHandler,
 	return &NatsHandler{
 		nc:         nc,
 		ruleEngine: ruleEngine,
+		store:      store,
+		opts:       opts,
 		validator:  validator,
 	}, nil
 }
 
 func (h *NatsHandler) HandleRequests() error {
-	if _, err := h.nc.Subscribe(SetPolicy, h.handleSetPolicy); err != nil {
+	if err := h.subscribe(SetPolicy, h.handleSetPolicy); err != nil {
+		return err
+	}
+	if err := h.subscribe(ListPolicies, h.handleListPolicies); err != nil {
+		return err
+	}
+	if err := h.subscribe(GetPolicy, h.handleGetPolicy); err != nil {
+		return err
+	}
+	if err := h.subscribe(DeletePolicy, h.handleDeletePolicy); err != nil {
+		return err
+	}
+	if err := h.subscribe(ValidatePolicy, h.handleValidatePolicy); err != nil {
+		return err
+	}
+	if err := h.subscribe(ListRevisions, h.handleListRevisions); err != nil {
+		return err
+	}
+	if err := h.subscribe(GetRevision, h.handleGetRevision); err != nil {
+		return err
+	}
+	if err := h.subscribe(RollbackPolicy, h.handleRollbackPolicy); err != nil {
+		return err
+	}
+	if err := h.handleEditRequests(); err != nil {
+		return err
+	}
+	if err := h.subscribe(SetDescriptorSet, h.handleSetDescriptorSet); err != nil {
+		return err
+	}
+	if err := h.subscribe(ListDescriptorSets, h.handleListDescriptorSets); err != nil {
+		return err
+	}
+	if err := h.subscribe(DeleteDescriptorSet, h.handleDeleteDescriptorSet); err != nil {
+		return err
+	}
+	if err := h.subscribe(SetInputBinding, h.handleSetInputBinding); err != nil {
+		return err
+	}
+	if err := h.subscribe(ListInputBindings, h.handleListInputBindings); err != nil {
+		return err
+	}
+	if err := h.subscribe(DeleteInputBinding, h.handleDeleteInputBinding); err != nil {
 		return err
 	}
-	if _, err := h.nc.Subscribe(ListPolicies, h.handleListPolicies); err != nil {
+	if err := h.subscribe(SetCalendar, h.handleSetCalendar); err != nil {
 		return err
 	}
-	if _, err := h.nc.Subscribe(GetPolicy, h.handleGetPolicy); err != nil {
+	if err := h.subscribe(ListCalendars, h.handleListCalendars); err != nil {
 		return err
 	}
-	if _, err := h.nc.Subscribe(DeletePolicy, h.handleDeletePolicy); err != nil {
+	if err := h.subscribe(DeleteCalendar, h.handleDeleteCalendar); err != nil {
+		return err
+	}
+	// Every instance must answer, whatever the sync mode
+	if _, err := h.nc.Subscribe(PolicySetHash, h.handlePolicySetHash); err != nil {
 		return err
 	}
 	return nil
 }
 
+func (h *NatsHandler) subscribe(subject string, handler nats.MsgHandler) error {
+	var err error
+	if h.opts.SyncMode == SyncWatch {
+		_, err = h.nc.QueueSubscribe(subject, QueueGroup, handler)
+	} else {
+		_, err = h.nc.Subscribe(subject, handler)
+	}
+	return err
+}
+
 func (h *NatsHandler) handleSetPolicy(msg *nats.Msg) {
 	var req SetPolicyRequest
 	if err := proto.Unmarshal(msg.Data, &req); err != nil {
@@ -72,8 +201,20 @@ func (h *NatsHandler) handleSetPolicy(msg *nats.Msg) {
 		return
 	}
 
-	policy := convertProtoToModelPolicy(req.Policy)
-	err := h.ruleEngine.AddPolicy(policy)
+	policy, err := convertProtoToModelPolicy(req.Policy)
+	if err != nil {
+		slog.Error("Error validating SetPolicy request", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+	rev, err := h.writePolicy(policy, engine.WriteOptions{
+		Author:           req.Author,
+		Note:             req.Note,
+		ExpectedRevision: req.ExpectedRevision,
+		CreateOnly:       req.CreateOnly,
+	})
 	if err != nil {
 		slog.Error("Error adding policy", "error", err)
 		if err := h.replyWithError(msg, err); err != nil {
@@ -82,12 +223,63 @@ func (h *NatsHandler) handleSetPolicy(msg *nats.Msg) {
 		return
 	}
 
-	resp := &SetPolicyResponse{Success: true}
+	resp := &SetPolicyResponse{Success: true, Revision: rev.Revision}
 	if err := h.replyWithProto(msg, resp); err != nil {
 		slog.Error("Error sending response", "error", err)
 	}
 }
 
+// writePolicy persists a policy as a new revision and makes it current.
+func (h *NatsHandler) writePolicy(policy models.Policy, opts engine.WriteOptions) (models.PolicyRevision, error) {
+	return h.writeRevision(func() (models.PolicyRevision, error) {
+		return h.ruleEngine.PrepareRevision(policy, opts)
+	})
+}
+
+// writeRevision persists the revision returned by prepare and then, in local
+// mode, makes it current, so that the engine never serves a policy that is
+// not stored. In watch mode the revision is only written to the store, and
+// every instance, including this one, applies it from there.
+func (h *NatsHandler) writeRevision(prepare func() (models.PolicyRevision, error)) (models.PolicyRevision, error) {
+	if h.opts.SyncMode != SyncWatch {
+		h.writeMu.Lock()
+		defer h.writeMu.Unlock()
+	}
+
+	rev, err := prepare()
+	if err != nil {
+		return models.PolicyRevision{}, err
+	}
+	if err := h.storeRevision(rev); err != nil {
+		return models.PolicyRevision{}, err
+	}
+	if h.opts.SyncMode != SyncWatch {
+		if err := h.ruleEngine.CommitRevision(rev); err != nil {
+			return models.PolicyRevision{}, err
+		}
+	}
+	return rev, nil
+}
+
+// storeRevision persists a revision and makes it the current definition of
+// its policy in the store.
+func (h *NatsHandler) storeRevision(rev models.PolicyRevision) error {
+	protoRev := convertModelToProtoRevision(rev, true)
+	if h.opts.SyncMode == SyncWatch {
+		// Creating the revision fails if another instance has just written
+		// the same revision number, so only one of the writes wins
+		if err := h.store.CreateRevision(protoRev); err != nil {
+			if errors.Is(err, nats.ErrKeyExists) {
+				return fmt.Errorf("%w: revision %d of policy %s was written concurrently", engine.ErrRevisionConflict, rev.Revision, rev.Policy.ID)
+			}
+			return err
+		}
+	} else if err := h.store.SaveRevision(protoRev); err != nil {
+		return err
+	}
+	return h.store.SavePolicy(protoRev.Policy)
+}
+
 func (h *NatsHandler) handleListPolicies(msg *nats.Msg) {
 	policies := h.ruleEngine.GetAllPolicies()
 	resp := &ListPoliciesResponse{
@@ -154,8 +346,7 @@ func (h *NatsHandler) handleDeletePolicy(msg *nats.Msg) {
 		return
 	}
 
-	err := h.ruleEngine.DeletePolicy(req.Id)
-	if err != nil {
+	if err := h.removePolicy(req.Id, engine.WriteOptions{ExpectedRevision: req.ExpectedRevision}); err != nil {
 		slog.Error("Error deleting policy", "error", err)
 		if err := h.replyWithError(msg, err); err != nil {
 			slog.Error("Error sending error response", "error", err)
@@ -169,11 +360,110 @@ func (h *NatsHandler) handleDeletePolicy(msg *nats.Msg) {
 	}
 }
 
+// removePolicy deletes a policy from the store and then, in local mode, from
+// the engine. In watch mode the policy is removed by the store watcher; as
+// the engine may be behind the store, the policy is deleted only if it is
+// still stored at the expected revision, or at the one of the engine, so a
+// deletion racing with a write of another instance fails with a conflict.
+func (h *NatsHandler) removePolicy(id string, opts engine.WriteOptions) error {
+	if h.opts.SyncMode != SyncWatch {
+		h.writeMu.Lock()
+		defer h.writeMu.Unlock()
+	}
+
+	if err := h.ruleEngine.CheckRemove(id, opts); err != nil {
+		return err
+	}
+	if h.opts.SyncMode == SyncWatch {
+		revision := opts.ExpectedRevision
+		if revision == 0 {
+			current, err := h.ruleEngine.GetPolicy(id)
+			if err != nil {
+				return err
+			}
+			revision = current.Revision
+		}
+		return h.store.DeletePolicyRevision(id, revision)
+	}
+	if err := h.store.DeletePolicy(id); err != nil {
+		return err
+	}
+	return h.ruleEngine.RemovePolicy(id, opts)
+}
+
+func (h *NatsHandler) handlePolicySetHash(msg *nats.Msg) {
+	policySet := h.ruleEngine.Snapshot()
+	hash, err := policySetHash(policySet)
+	if err != nil {
+		slog.Error("Error computing policy set hash", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	resp := &PolicySetHashResponse{
+		InstanceId:  h.opts.InstanceID,
+		Hash:        hash,
+		PolicyCount: int32(policySet.Len()),
+	}
+	if err := h.replyWithProto(msg, resp); err != nil {
+		slog.Error("Error sending response", "error", err)
+	}
+}
+
+// policySetHash returns a SHA-256 digest of the definitions of the policies
+// in the set. It does not depend on the order of the policies, so instances
+// running the same policies report the same hash.
+func policySetHash(policySet *engine.PolicySet) (string, error) {
+	policies := policySet.GetAllPolicies()
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].ID < policies[j].ID
+	})
+
+	marshal := proto.MarshalOptions{Deterministic: true}
+	digest := sha256.New()
+	for _, p := range policies {
+		// Instances that loaded the same definitions at different times may
+		// number them differently, so revisions are not part of the hash
+		p.Revision = 0
+		data, err := marshal.Marshal(convertModelToProtoPolicy(p))
+		if err != nil {
+			return "", fmt.Errorf("error serializing policy %s: %v", p.ID, err)
+		}
+		// Length prefix, so that different sets cannot produce the same stream
+		if err := binary.Write(digest, binary.BigEndian, uint64(len(data))); err != nil {
+			return "", err
+		}
+		digest.Write(data)
+	}
+	return hex.EncodeToString(digest.Sum(nil)), nil
+}
+
 func (h *NatsHandler) replyWithError(msg *nats.Msg, err error) error {
-	errResp := &ErrorResponse{Error: err.Error()}
+	errResp := &ErrorResponse{Error: err.Error(), Code: errorCode(err)}
 	return h.replyWithProto(msg, errResp)
 }
 
+func errorCode(err error) ErrorCode {
+	var validationErr *protovalidate.ValidationError
+	switch {
+	case errors.Is(err, proto.Error), errors.As(err, &validationErr), errors.Is(err, errUnknownEnum):
+		return ErrorCode_ERROR_CODE_INVALID_REQUEST
+	case errors.Is(err, engine.ErrPolicyNotFound), errors.Is(err, engine.ErrRevisionNotFound),
+		errors.Is(err, engine.ErrDescriptorSetNotFound), errors.Is(err, engine.ErrBindingNotFound),
+		errors.Is(err, engine.ErrCalendarNotFound), errors.Is(err, models.ErrRuleNotFound),
+		errors.Is(err, models.ErrThresholdNotFound):
+		return ErrorCode_ERROR_CODE_NOT_FOUND
+	case errors.Is(err, engine.ErrRevisionConflict):
+		return ErrorCode_ERROR_CODE_CONFLICT
+	case errors.Is(err, engine.ErrPolicyExists), errors.Is(err, models.ErrRuleExists):
+		return ErrorCode_ERROR_CODE_ALREADY_EXISTS
+	default:
+		return ErrorCode_ERROR_CODE_UNSPECIFIED
+	}
+}
+
 func (h *NatsHandler) replyWithProto(msg *nats.Msg, resp proto.Message) error {
 	if msg.Reply == "" {
 		slog.Warn("The message has no reply subject")
@@ -187,46 +477,533 @@ func (h *NatsHandler) replyWithProto(msg *nats.Msg, resp proto.Message) error {
 	return msg.Respond(data)
 }
 
+// errUnknownEnum is returned when a message has an enum value the engine
+// does not know, instead of falling back to the default value.
+var errUnknownEnum = errors.New("unknown enum value")
+
 // Helper functions to convert between proto and model types
-func convertProtoToModelPolicy(p *Policy) models.Policy {
+func convertProtoToModelPolicy(p *Policy) (models.Policy, error) {
+	rules, err := convertProtoToModelRules(p.Rules)
+	if err != nil {
+		return models.Policy{}, err
+	}
+	thresholds, err := convertProtoToModelThresholds(p.Thresholds)
+	if err != nil {
+		return models.Policy{}, err
+	}
+	aggregation, err := convertProtoToModelAggregation(p.Aggregation)
+	if err != nil {
+		return models.Policy{}, err
+	}
+	outcomeMode, ok := outcomeModes[p.OutcomeMode]
+	if !ok {
+		return models.Policy{}, fmt.Errorf("%w: outcome mode %d", errUnknownEnum, p.OutcomeMode)
+	}
+	direction, ok := thresholdDirections[p.ThresholdDirection]
+	if !ok {
+		return models.Policy{}, fmt.Errorf("%w: threshold direction %d", errUnknownEnum, p.ThresholdDirection)
+	}
 	return models.Policy{
-		ID:         p.Id,
-		Name:       p.Name,
-		Expression: p.Expression,
-		Rules:      convertProtoToModelRules(p.Rules),
-		Thresholds: convertProtoToModelThresholds(p.Thresholds),
+		ID:                 p.Id,
+		Revision:           p.Revision,
+		Name:               p.Name,
+		InputSchema:        convertProtoToModelInputSchema(p.InputSchema),
+		Expression:         p.Expression,
+		Rules:              rules,
+		Thresholds:         thresholds,
+		Geofences:          convertProtoToModelGeofences(p.Geofences),
+		Aggregation:        aggregation,
+		OutcomeMode:        outcomeMode,
+		ThresholdDirection: direction,
+		Outputs:            convertProtoToModelOutputs(p.Outputs),
+		MaxReasons:         int(p.MaxReasons),
+		Explain:            p.Explain,
+		LibraryVersion:     p.LibraryVersion,
+	}, nil
+}
+
+func convertProtoToModelInputSchema(s *InputSchema) models.InputSchema {
+	return models.InputSchema{
+		JSONSchema:  s.GetJsonSchema(),
+		MessageType: s.GetMessageType(),
 	}
 }
 
-func convertProtoToModelRules(protoRules []*Rule) []models.Rule {
+func convertProtoToModelRules(protoRules []*Rule) ([]models.Rule, error) {
 	rules := make([]models.Rule, len(protoRules))
 	for i, r := range protoRules {
-		rules[i] = models.Rule{
-			Name:       r.Name,
-			Expression: r.Expression,
+		rule, err := convertProtoToModelRule(r)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %w", r.Name, err)
 		}
+		rules[i] = rule
 	}
-	return rules
+	return rules, nil
 }
 
-func convertProtoToModelThresholds(protoThresholds []*Threshold) []models.Threshold {
+func convertProtoToModelRule(r *Rule) (models.Rule, error) {
+	group, err := convertProtoToModelRuleGroup(r.Group)
+	if err != nil {
+		return models.Rule{}, err
+	}
+	return models.Rule{
+		Name:       r.Name,
+		Expression: r.Expression,
+		Disabled:   r.Disabled,
+		Weight:     r.Weight,
+		Multiplier: r.Multiplier,
+		MinScore:   r.MinScore,
+		MaxScore:   r.MaxScore,
+		Outcome:    r.Outcome,
+		Priority:   r.Priority,
+		When:       r.When,
+		Group:      group,
+	}, nil
+}
+
+func convertProtoToModelRuleGroup(g *RuleGroup) (*models.RuleGroup, error) {
+	if g == nil {
+		return nil, nil
+	}
+	rules, err := convertProtoToModelRules(g.Rules)
+	if err != nil {
+		return nil, err
+	}
+	aggregation, err := convertProtoToModelAggregation(g.Aggregation)
+	if err != nil {
+		return nil, err
+	}
+	thresholds, err := convertProtoToModelThresholds(g.Thresholds)
+	if err != nil {
+		return nil, err
+	}
+	return &models.RuleGroup{
+		Rules:         rules,
+		Aggregation:   aggregation,
+		PropagateStop: g.PropagateStop,
+		Thresholds:    thresholds,
+	}, nil
+}
+
+func convertProtoToModelThresholds(protoThresholds []*Threshold) ([]models.Threshold, error) {
 	thresholds := make([]models.Threshold, len(protoThresholds))
 	for i, t := range protoThresholds {
-		thresholds[i] = models.Threshold{
-			ID:    t.Id,
-			Value: t.Value,
+		threshold, err := convertProtoToModelThreshold(t)
+		if err != nil {
+			return nil, fmt.Errorf("threshold %s: %w", t.Id, err)
 		}
+		thresholds[i] = threshold
 	}
-	return thresholds
+	return thresholds, nil
+}
+
+func convertProtoToModelThreshold(t *Threshold) (models.Threshold, error) {
+	value := float64(t.LegacyValue)
+	if t.Value != nil {
+		value = *t.Value
+	}
+	actions, err := convertProtoToModelActions(t.Actions)
+	if err != nil {
+		return models.Threshold{}, err
+	}
+	return models.Threshold{
+		ID:        t.Id,
+		Value:     value,
+		Range:     convertProtoToModelScoreRange(t.Range),
+		Condition: t.Condition,
+		Disabled:  t.Disabled,
+		Actions:   actions,
+	}, nil
+}
+
+// actionTypes maps the action types of the API to the ones of the engine.
+var actionTypes = map[ActionType]models.ActionType{
+	ActionType_ACTION_TYPE_PUBLISH:       models.ActionPublish,
+	ActionType_ACTION_TYPE_SET_ATTRIBUTE: models.ActionSetAttribute,
+	ActionType_ACTION_TYPE_ADD_TAG:       models.ActionAddTag,
+	ActionType_ACTION_TYPE_OBLIGATION:    models.ActionObligation,
+}
+
+func convertProtoToModelActions(protoActions []*Action) ([]models.Action, error) {
+	if len(protoActions) == 0 {
+		return nil, nil
+	}
+	actions := make([]models.Action, len(protoActions))
+	for i, a := range protoActions {
+		actionType, ok := actionTypes[a.Type]
+		if !ok {
+			return nil, fmt.Errorf("%w: action type %d", errUnknownEnum, a.Type)
+		}
+		actions[i] = models.Action{
+			Type:       actionType,
+			Subject:    a.Subject,
+			Name:       a.Name,
+			Value:      a.Value,
+			Parameters: a.Parameters,
+			Expression: a.Expression,
+		}
+	}
+	return actions, nil
+}
+
+func convertModelToProtoActions(modelActions []models.Action) []*Action {
+	actions := make([]*Action, len(modelActions))
+	for i, a := range modelActions {
+		actions[i] = &Action{
+			Subject:    a.Subject,
+			Name:       a.Name,
+			Value:      a.Value,
+			Parameters: a.Parameters,
+			Expression: a.Expression,
+		}
+		for t, at := range actionTypes {
+			if at == a.Type {
+				actions[i].Type = t
+			}
+		}
+	}
+	return actions
+}
+
+// ConvertModelToProtoObligations converts the obligations emitted by the
+// actions of a threshold to their API messages.
+func ConvertModelToProtoObligations(modelObligations []models.Obligation) []*Obligation {
+	if len(modelObligations) == 0 {
+		return nil
+	}
+	obligations := make([]*Obligation, len(modelObligations))
+	for i, o := range modelObligations {
+		obligations[i] = &Obligation{
+			Name:       o.Name,
+			Parameters: o.Parameters,
+		}
+	}
+	return obligations
+}
+
+// ConvertModelToProtoRuleResults converts the results of the rules of a
+// policy, in the order of the rules, to their API messages. The results of
+// the rules of the groups are nested in the results of the groups.
+func ConvertModelToProtoRuleResults(rules []models.Rule, modelResults []models.RuleResult) ([]*RuleResult, error) {
+	results := make([]*RuleResult, len(modelResults))
+	for i, rr := range modelResults {
+		metadata, err := ConvertModelToProtoValues(rr.Metadata)
+		if err != nil {
+			return nil, fmt.Errorf("error converting metadata of rule %s: %v", rules[i].Name, err)
+		}
+		var groupResults []*RuleResult
+		if rules[i].Group != nil && len(rr.Rules) > 0 {
+			if groupResults, err = ConvertModelToProtoRuleResults(rules[i].Group.Rules, rr.Rules); err != nil {
+				return nil, err
+			}
+		}
+		results[i] = &RuleResult{
+			Name:          rules[i].Name,
+			Score:         rr.Score,
+			LegacyScore:   int64(rr.Score),
+			Stop:          rr.Stop,
+			Passed:        rr.Passed,
+			Executed:      rr.Executed,
+			NotApplicable: rr.NotApplicable,
+			ReasonCode:    rr.ReasonCode,
+			Message:       rr.Message,
+			Metadata:      metadata,
+			Threshold:     rr.Threshold,
+			Rules:         groupResults,
+		}
+	}
+	return results, nil
+}
+
+// ConvertModelToProtoReasons converts the ranked reasons of a policy result
+// to their API messages.
+func ConvertModelToProtoReasons(modelReasons []models.Reason) []*Reason {
+	if len(modelReasons) == 0 {
+		return nil
+	}
+	reasons := make([]*Reason, len(modelReasons))
+	for i, r := range modelReasons {
+		reasons[i] = &Reason{
+			Code:    r.Code,
+			Message: r.Message,
+			Rule:    r.Rule,
+			Score:   r.Score,
+		}
+	}
+	return reasons
+}
+
+// ruleStatuses maps the rule statuses of the engine to the ones of the API.
+var ruleStatuses = map[models.RuleStatus]RuleStatus{
+	models.RuleExecuted:      RuleStatus_RULE_STATUS_EXECUTED,
+	models.RuleDisabled:      RuleStatus_RULE_STATUS_DISABLED,
+	models.RuleSkipped:       RuleStatus_RULE_STATUS_SKIPPED,
+	models.RuleNotApplicable: RuleStatus_RULE_STATUS_NOT_APPLICABLE,
+}
+
+// ConvertModelToProtoTrace converts the trace of an explained evaluation to
+// its API message.
+func ConvertModelToProtoTrace(t *models.Trace) *Trace {
+	if t == nil {
+		return nil
+	}
+	trace := &Trace{
+		Gate:       convertModelToProtoExpressionTrace(t.Gate),
+		Executed:   t.Executed,
+		Rules:      convertModelToProtoRuleTraces(t.Rules),
+		Thresholds: make([]*ThresholdTrace, len(t.Thresholds)),
+	}
+	for i, th := range t.Thresholds {
+		trace.Thresholds[i] = &ThresholdTrace{
+			Id:        th.ID,
+			Disabled:  th.Disabled,
+			Position:  th.Position,
+			Reached:   th.Reached,
+			Condition: th.Condition,
+		}
+	}
+	return trace
+}
+
+func convertModelToProtoRuleTraces(modelTraces []models.RuleTrace) []*RuleTrace {
+	if len(modelTraces) == 0 {
+		return nil
+	}
+	traces := make([]*RuleTrace, len(modelTraces))
+	for i, r := range modelTraces {
+		traces[i] = &RuleTrace{
+			Name:       r.Name,
+			Status:     ruleStatuses[r.Status],
+			StoppedBy:  r.StoppedBy,
+			Duration:   durationpb.New(r.Duration),
+			Expression: convertModelToProtoExpressionTrace(r.Expression),
+			Rules:      convertModelToProtoRuleTraces(r.Rules),
+		}
+	}
+	return traces
+}
+
+func convertModelToProtoExpressionTrace(t *models.ExpressionTrace) *ExpressionTrace {
+	if t == nil {
+		return nil
+	}
+	trace := &ExpressionTrace{
+		Value:          traceProtoValue(t.Value),
+		Error:          t.Error,
+		SubExpressions: make([]*SubExpression, len(t.SubExpressions)),
+	}
+	for i, sub := range t.SubExpressions {
+		trace.SubExpressions[i] = &SubExpression{
+			Expression: sub.Expression,
+			Value:      traceProtoValue(sub.Value),
+		}
+	}
+	return trace
+}
+
+// traceProtoValue converts a value of a trace, falling back to its string
+// representation, since a trace is reported even if some of its values
+// cannot be converted.
+func traceProtoValue(v interface{}) *structpb.Value {
+	if value, err := structpb.NewValue(protoValue(v)); err == nil {
+		return value
+	}
+	return structpb.NewStringValue(fmt.Sprint(v))
+}
+
+// ConvertModelToProtoValues converts the values computed by expressions, like
+// the outputs of a policy and the metadata of a rule result, to protobuf
+// values. Decimals are converted to strings, to keep all their digits, and
+// times to RFC 3339 strings.
+func ConvertModelToProtoValues(values map[string]interface{}) (map[string]*structpb.Value, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	protoValues := make(map[string]*structpb.Value, len(values))
+	for name, v := range values {
+		value, err := structpb.NewValue(protoValue(v))
+		if err != nil {
+			return nil, fmt.Errorf("error converting %s: %v", name, err)
+		}
+		protoValues[name] = value
+	}
+	return protoValues, nil
+}
+
+// protoValue replaces the values that structpb does not convert.
+func protoValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case rcel.Decimal:
+		return v.String()
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case map[string]interface{}:
+		values := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			values[key] = protoValue(item)
+		}
+		return values
+	case []interface{}:
+		values := make([]interface{}, len(v))
+		for i, item := range v {
+			values[i] = protoValue(item)
+		}
+		return values
+	}
+	return value
+}
+
+func convertProtoToModelOutputs(protoOutputs []*Output) []models.Output {
+	if len(protoOutputs) == 0 {
+		return nil
+	}
+	outputs := make([]models.Output, len(protoOutputs))
+	for i, o := range protoOutputs {
+		outputs[i] = models.Output{
+			Name:       o.Name,
+			Expression: o.Expression,
+		}
+	}
+	return outputs
+}
+
+func convertModelToProtoOutputs(modelOutputs []models.Output) []*Output {
+	outputs := make([]*Output, len(modelOutputs))
+	for i, o := range modelOutputs {
+		outputs[i] = &Output{
+			Name:       o.Name,
+			Expression: o.Expression,
+		}
+	}
+	return outputs
+}
+
+func convertProtoToModelScoreRange(r *ScoreRange) *models.ScoreRange {
+	if r == nil {
+		return nil
+	}
+	return &models.ScoreRange{
+		Min:          r.Min,
+		Max:          r.Max,
+		MinExclusive: r.MinExclusive,
+		MaxExclusive: r.MaxExclusive,
+	}
+}
+
+// thresholdDirections maps the threshold directions of the API to the ones of
+// the engine. The unspecified direction is the default one.
+var thresholdDirections = map[ThresholdDirection]models.ThresholdDirection{
+	ThresholdDirection_THRESHOLD_DIRECTION_UNSPECIFIED: "",
+	ThresholdDirection_THRESHOLD_DIRECTION_ASCENDING:   models.ThresholdAscending,
+	ThresholdDirection_THRESHOLD_DIRECTION_DESCENDING:  models.ThresholdDescending,
+}
+
+func convertModelToProtoThresholdDirection(direction models.ThresholdDirection) ThresholdDirection {
+	for d, td := range thresholdDirections {
+		if td == direction {
+			return d
+		}
+	}
+	return ThresholdDirection_THRESHOLD_DIRECTION_UNSPECIFIED
+}
+
+func convertProtoToModelGeofences(protoGeofences []*Geofence) []models.Geofence {
+	geofences := make([]models.Geofence, len(protoGeofences))
+	for i, g := range protoGeofences {
+		geofences[i] = models.Geofence{
+			Name:    g.Name,
+			GeoJSON: g.Geojson,
+		}
+	}
+	return geofences
+}
+
+// aggregationModes maps the aggregation modes of the API to the ones of the
+// engine. The unspecified mode is the default one.
+var aggregationModes = map[AggregationMode]models.AggregationMode{
+	AggregationMode_AGGREGATION_MODE_UNSPECIFIED:      "",
+	AggregationMode_AGGREGATION_MODE_SUM:              models.AggregationSum,
+	AggregationMode_AGGREGATION_MODE_MAX:              models.AggregationMax,
+	AggregationMode_AGGREGATION_MODE_MIN:              models.AggregationMin,
+	AggregationMode_AGGREGATION_MODE_AVERAGE:          models.AggregationAverage,
+	AggregationMode_AGGREGATION_MODE_WEIGHTED_AVERAGE: models.AggregationWeightedAverage,
+	AggregationMode_AGGREGATION_MODE_PRODUCT:          models.AggregationProduct,
+	AggregationMode_AGGREGATION_MODE_CUSTOM:           models.AggregationCustom,
+}
+
+func convertProtoToModelAggregation(a *Aggregation) (models.Aggregation, error) {
+	mode, ok := aggregationModes[a.GetMode()]
+	if !ok {
+		return models.Aggregation{}, fmt.Errorf("%w: aggregation mode %d", errUnknownEnum, a.GetMode())
+	}
+	return models.Aggregation{
+		Mode:       mode,
+		Expression: a.GetExpression(),
+	}, nil
+}
+
+func convertModelToProtoAggregation(a models.Aggregation) *Aggregation {
+	if a.Mode == "" && a.Expression == "" {
+		return nil
+	}
+	for mode, m := range aggregationModes {
+		if m == a.Mode {
+			return &Aggregation{Mode: mode, Expression: a.Expression}
+		}
+	}
+	return &Aggregation{Expression: a.Expression}
+}
+
+// outcomeModes maps the outcome modes of the API to the ones of the engine.
+// The unspecified mode is the default one.
+var outcomeModes = map[OutcomeMode]models.OutcomeMode{
+	OutcomeMode_OUTCOME_MODE_UNSPECIFIED: "",
+	OutcomeMode_OUTCOME_MODE_THRESHOLD:   models.OutcomeThreshold,
+	OutcomeMode_OUTCOME_MODE_ALL_PASS:    models.OutcomeAllPass,
+	OutcomeMode_OUTCOME_MODE_ANY_PASS:    models.OutcomeAnyPass,
+	OutcomeMode_OUTCOME_MODE_FIRST:       models.OutcomeFirst,
+	OutcomeMode_OUTCOME_MODE_UNIQUE:      models.OutcomeUnique,
+	OutcomeMode_OUTCOME_MODE_PRIORITY:    models.OutcomePriority,
+	OutcomeMode_OUTCOME_MODE_COLLECT:     models.OutcomeCollect,
+}
+
+func convertModelToProtoOutcomeMode(mode models.OutcomeMode) OutcomeMode {
+	for m, om := range outcomeModes {
+		if om == mode {
+			return m
+		}
+	}
+	return OutcomeMode_OUTCOME_MODE_UNSPECIFIED
 }
 
 func convertModelToProtoPolicy(p models.Policy) *Policy {
 	return &Policy{
-		Id:         p.ID,
-		Name:       p.Name,
-		Expression: p.Expression,
-		Rules:      convertModelToProtoRules(p.Rules),
-		Thresholds: convertModelToProtoThresholds(p.Thresholds),
+		Id:                 p.ID,
+		Name:               p.Name,
+		InputSchema:        convertModelToProtoInputSchema(p.InputSchema),
+		Expression:         p.Expression,
+		Rules:              convertModelToProtoRules(p.Rules),
+		Thresholds:         convertModelToProtoThresholds(p.Thresholds),
+		Geofences:          convertModelToProtoGeofences(p.Geofences),
+		Aggregation:        convertModelToProtoAggregation(p.Aggregation),
+		OutcomeMode:        convertModelToProtoOutcomeMode(p.OutcomeMode),
+		Revision:           p.Revision,
+		ThresholdDirection: convertModelToProtoThresholdDirection(p.ThresholdDirection),
+		Outputs:            convertModelToProtoOutputs(p.Outputs),
+		MaxReasons:         uint32(p.MaxReasons),
+		Explain:            p.Explain,
+		LibraryVersion:     p.LibraryVersion,
+	}
+}
+
+func convertModelToProtoInputSchema(s models.InputSchema) *InputSchema {
+	switch {
+	case s.JSONSchema != "":
+		return &InputSchema{Source: &InputSchema_JsonSchema{JsonSchema: s.JSONSchema}}
+	case s.MessageType != "":
+		return &InputSchema{Source: &InputSchema_MessageType{MessageType: s.MessageType}}
+	default:
+		return nil
 	}
 }
 
@@ -236,22 +1013,71 @@ func convertModelToProtoRules(modelRules []models.Rule) []*Rule {
 		rules[i] = &Rule{
 			Name:       r.Name,
 			Expression: r.Expression,
+			Disabled:   r.Disabled,
+			Weight:     r.Weight,
+			Multiplier: r.Multiplier,
+			MinScore:   r.MinScore,
+			MaxScore:   r.MaxScore,
+			Outcome:    r.Outcome,
+			Priority:   r.Priority,
+			When:       r.When,
+			Group:      convertModelToProtoRuleGroup(r.Group),
 		}
 	}
 	return rules
 }
 
+func convertModelToProtoRuleGroup(g *models.RuleGroup) *RuleGroup {
+	if g == nil {
+		return nil
+	}
+	return &RuleGroup{
+		Rules:         convertModelToProtoRules(g.Rules),
+		Aggregation:   convertModelToProtoAggregation(g.Aggregation),
+		PropagateStop: g.PropagateStop,
+		Thresholds:    convertModelToProtoThresholds(g.Thresholds),
+	}
+}
+
 func convertModelToProtoThresholds(modelThresholds []models.Threshold) []*Threshold {
 	thresholds := make([]*Threshold, len(modelThresholds))
 	for i, t := range modelThresholds {
 		thresholds[i] = &Threshold{
-			Id:    t.ID,
-			Value: int64(t.Value), // Converted from int to int64
+			Id:          t.ID,
+			Value:       proto.Float64(t.Value),
+			LegacyValue: int64(t.Value),
+			Range:       convertModelToProtoScoreRange(t.Range),
+			Condition:   t.Condition,
+			Disabled:    t.Disabled,
+			Actions:     convertModelToProtoActions(t.Actions),
 		}
 	}
 	return thresholds
 }
 
+func convertModelToProtoScoreRange(r *models.ScoreRange) *ScoreRange {
+	if r == nil {
+		return nil
+	}
+	return &ScoreRange{
+		Min:          r.Min,
+		Max:          r.Max,
+		MinExclusive: r.MinExclusive,
+		MaxExclusive: r.MaxExclusive,
+	}
+}
+
+func convertModelToProtoGeofences(modelGeofences []models.Geofence) []*Geofence {
+	geofences := make([]*Geofence, len(modelGeofences))
+	for i, g := range modelGeofences {
+		geofences[i] = &Geofence{
+			Name:    g.Name,
+			Geojson: g.GeoJSON,
+		}
+	}
+	return geofences
+}
+
 type InputAck struct {
 	Success bool   `json:"success"`
 	Message string `json:"message"`
@@ -0,0 +1,201 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bufbuild/protovalidate-go"
+
+	"github.com/sandrolain/rules/engine"
+	"github.com/sandrolain/rules/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNatsHandler_WriteFailure(t *testing.T) {
+	nc, js := runJetStream(t)
+	h := newTestHandler(t, nc, js, SyncLocal)
+	policy := models.Policy{ID: "payments", Rules: []models.Rule{{Name: "Amount", Expression: "input.amount"}}}
+
+	_, err := h.writePolicy(policy, engine.WriteOptions{})
+	require.NoError(t, err)
+
+	// Without the bucket every write to the store fails
+	require.NoError(t, js.DeleteKeyValue(testBucket))
+
+	t.Run("Set", func(t *testing.T) {
+		edited := policy.Clone()
+		edited.Rules[0].Expression = "input.amount * 2"
+		_, err := h.writePolicy(edited, engine.WriteOptions{})
+		assert.Error(t, err)
+
+		current, err := h.ruleEngine.GetPolicy("payments")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), current.Revision)
+		assert.Equal(t, "input.amount", current.Rules[0].Expression)
+		revisions, _ := h.ruleEngine.ListRevisions("payments")
+		assert.Len(t, revisions, 1)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		assert.Error(t, h.removePolicy("payments", engine.WriteOptions{}))
+		_, err := h.ruleEngine.GetPolicy("payments")
+		assert.NoError(t, err)
+	})
+}
+
+func TestConvertProtoToModelPolicy_UnknownEnum(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy *Policy
+	}{
+		{"Outcome mode", &Policy{Id: "p", OutcomeMode: 99}},
+		{"Threshold direction", &Policy{Id: "p", ThresholdDirection: 99}},
+		{"Aggregation mode", &Policy{Id: "p", Aggregation: &Aggregation{Mode: 99}}},
+		{"Action type", &Policy{Id: "p", Thresholds: []*Threshold{{Id: "high", Actions: []*Action{{Type: 99}}}}}},
+		{"Group aggregation mode", &Policy{Id: "p", Rules: []*Rule{{Name: "Group", Group: &RuleGroup{Aggregation: &Aggregation{Mode: 99}}}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := convertProtoToModelPolicy(tt.policy)
+			assert.ErrorIs(t, err, errUnknownEnum)
+			assert.Equal(t, ErrorCode_ERROR_CODE_INVALID_REQUEST, errorCode(err))
+		})
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	validator, err := protovalidate.New()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		err  error
+		code ErrorCode
+	}{
+		{"Policy not found", fmt.Errorf("wrapped: %w", engine.ErrPolicyNotFound), ErrorCode_ERROR_CODE_NOT_FOUND},
+		{"Revision not found", engine.ErrRevisionNotFound, ErrorCode_ERROR_CODE_NOT_FOUND},
+		{"Descriptor set not found", engine.ErrDescriptorSetNotFound, ErrorCode_ERROR_CODE_NOT_FOUND},
+		{"Binding not found", engine.ErrBindingNotFound, ErrorCode_ERROR_CODE_NOT_FOUND},
+		{"Calendar not found", engine.ErrCalendarNotFound, ErrorCode_ERROR_CODE_NOT_FOUND},
+		{"Rule not found", fmt.Errorf("wrapped: %w", models.ErrRuleNotFound), ErrorCode_ERROR_CODE_NOT_FOUND},
+		{"Threshold not found", models.ErrThresholdNotFound, ErrorCode_ERROR_CODE_NOT_FOUND},
+		{"Revision conflict", engine.ErrRevisionConflict, ErrorCode_ERROR_CODE_CONFLICT},
+		{"Policy exists", engine.ErrPolicyExists, ErrorCode_ERROR_CODE_ALREADY_EXISTS},
+		{"Rule exists", models.ErrRuleExists, ErrorCode_ERROR_CODE_ALREADY_EXISTS},
+		{"Malformed message", proto.Unmarshal([]byte{0xff}, &SetPolicyRequest{}), ErrorCode_ERROR_CODE_INVALID_REQUEST},
+		{"Constraint violation", validator.Validate(&SetPolicyRequest{}), ErrorCode_ERROR_CODE_INVALID_REQUEST},
+		{"Unknown enum value", errUnknownEnum, ErrorCode_ERROR_CODE_INVALID_REQUEST},
+		{"Other", errors.New("boom"), ErrorCode_ERROR_CODE_UNSPECIFIED},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Error(t, tt.err)
+			assert.Equal(t, tt.code, errorCode(tt.err))
+		})
+	}
+}
+
+func TestConvertModelToProtoRuleResults_LegacyScore(t *testing.T) {
+	rules := []models.Rule{
+		{Name: "Probability", Expression: "0.75"},
+		{Name: "Group", Group: &models.RuleGroup{Rules: []models.Rule{{Name: "Penalty", Expression: "-2.5"}}}},
+	}
+	results, err := ConvertModelToProtoRuleResults(rules, []models.RuleResult{
+		{Score: 12.75, Executed: true},
+		{Score: -2.5, Executed: true, Rules: []models.RuleResult{{Score: -2.5, Executed: true}}},
+	})
+	require.NoError(t, err)
+
+	// Old clients read the truncated score from field 1
+	assert.Equal(t, 12.75, results[0].Score)
+	assert.Equal(t, int64(12), results[0].LegacyScore)
+	assert.Equal(t, int64(-2), results[1].LegacyScore)
+	assert.Equal(t, int64(-2), results[1].Rules[0].LegacyScore)
+}
+
+func TestConvertThreshold_LegacyValue(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold *Threshold
+		expected  float64
+	}{
+		{"Value", &Threshold{Id: "high", Value: proto.Float64(12.5), LegacyValue: 12}, 12.5},
+		{"Zero value", &Threshold{Id: "low", Value: proto.Float64(0), LegacyValue: 30}, 0},
+		{"Legacy value", &Threshold{Id: "high", LegacyValue: 30}, 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			threshold, err := convertProtoToModelThreshold(tt.threshold)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, threshold.Value)
+		})
+	}
+
+	// Old clients read the truncated value from field 2
+	thresholds := convertModelToProtoThresholds([]models.Threshold{{ID: "high", Value: 12.5}, {ID: "low", Value: -0.5}})
+	assert.Equal(t, int64(12), thresholds[0].LegacyValue)
+	assert.Equal(t, 12.5, thresholds[0].GetValue())
+	assert.Equal(t, int64(0), thresholds[1].LegacyValue)
+	assert.NotNil(t, thresholds[1].Value)
+}
+
+func TestConvertPolicy_RoundTrip(t *testing.T) {
+	ptr := func(f float64) *float64 { return &f }
+	policy := models.Policy{
+		ID:             "onboarding",
+		Revision:       3,
+		Name:           "Onboarding",
+		Expression:     "input.amount > 0",
+		LibraryVersion: 4,
+		Rules: []models.Rule{
+			{
+				Name:   "Identity",
+				When:   "input.country != 'IT'",
+				Weight: ptr(0.5),
+				Group: &models.RuleGroup{
+					Aggregation:   models.Aggregation{Mode: models.AggregationMax},
+					PropagateStop: true,
+					Thresholds: []models.Threshold{
+						{ID: "clear", Value: 0},
+						{ID: "suspect", Range: &models.ScoreRange{Min: ptr(30), MaxExclusive: true, Max: ptr(80)}},
+					},
+					Rules: []models.Rule{
+						{Name: "Document", Expression: "Result(input.document ? 0 : 30, false, 'NO_DOCUMENT')"},
+						{
+							Name: "Sanctions",
+							When: "has(input.name)",
+							Group: &models.RuleGroup{
+								Aggregation: models.Aggregation{Mode: models.AggregationCustom, Expression: "scores.max()"},
+								Thresholds:  []models.Threshold{{ID: "listed", Value: 100}},
+								Rules: []models.Rule{
+									{Name: "EU", Expression: "input.name in ['x'] ? 100 : 0", MaxScore: ptr(100)},
+									{Name: "UN", Expression: "0", Disabled: true},
+								},
+							},
+						},
+					},
+				},
+			},
+			{Name: "Amount", Expression: "input.amount / 10", Multiplier: ptr(1.5), Priority: 2},
+		},
+		Thresholds:         []models.Threshold{{ID: "low", Value: 0}, {ID: "high", Value: 50}},
+		Geofences:          []models.Geofence{},
+		Aggregation:        models.Aggregation{Mode: models.AggregationSum},
+		ThresholdDirection: models.ThresholdDescending,
+	}
+
+	// The message survives the wire too
+	data, err := proto.Marshal(convertModelToProtoPolicy(policy))
+	require.NoError(t, err)
+	var message Policy
+	require.NoError(t, proto.Unmarshal(data, &message))
+	converted, err := convertProtoToModelPolicy(&message)
+	require.NoError(t, err)
+	assert.Equal(t, policy, converted)
+}
@@ -0,0 +1,549 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nats-io/nats.go"
+	"github.com/sandrolain/rules/engine"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const (
+	policyKeyPrefix     = "policy."
+	revisionKeyPrefix   = "revision."
+	descriptorKeyPrefix = "descriptor."
+	bindingKeyPrefix    = "binding."
+	calendarKeyPrefix   = "calendar."
+)
+
+// entryOrder is the order the initial content of the bucket is applied in by
+// Watch: the resources used by the policies come first, whatever the order
+// they were written in.
+var entryOrder = []string{descriptorKeyPrefix, bindingKeyPrefix, calendarKeyPrefix, revisionKeyPrefix, policyKeyPrefix}
+
+// PolicyStore persists the policies set through the management API in a
+// JetStream key-value bucket, so that they survive a restart of the engine.
+//
+// The current definition of a policy is stored under "policy.<id>", and each
+// of its revisions under "revision.<id>.<revision>". The descriptor sets that
+// declare the input types of the policies are stored under
+// "descriptor.<name>", the input bindings under "binding.<subject>" and the
+// business calendars under "calendar.<name>".
+type PolicyStore struct {
+	kv nats.KeyValue
+}
+
+func NewPolicyStore(js nats.JetStreamContext, bucket string) (*PolicyStore, error) {
+	kv, err := js.KeyValue(bucket)
+	if errors.Is(err, nats.ErrBucketNotFound) {
+		kv, err = js.CreateKeyValue(&nats.KeyValueConfig{Bucket: bucket})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error opening key-value bucket %s: %w", bucket, err)
+	}
+	return &PolicyStore{kv: kv}, nil
+}
+
+func (s *PolicyStore) SavePolicy(p *Policy) error {
+	data, err := proto.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("error serializing policy %s: %w", p.Id, err)
+	}
+	if _, err := s.kv.Put(policyKeyPrefix+p.Id, data); err != nil {
+		return fmt.Errorf("error storing policy %s: %w", p.Id, err)
+	}
+	return nil
+}
+
+func (s *PolicyStore) DeletePolicy(id string) error {
+	if err := s.kv.Delete(policyKeyPrefix + id); err != nil && !errors.Is(err, nats.ErrKeyNotFound) {
+		return fmt.Errorf("error deleting policy %s: %w", id, err)
+	}
+	return nil
+}
+
+// DeletePolicyRevision deletes a policy only if its stored definition is at
+// revision. The deletion is conditional on the sequence of the definition in
+// the bucket, so it fails with engine.ErrRevisionConflict also if another
+// instance replaces the definition while it is being deleted.
+func (s *PolicyStore) DeletePolicyRevision(id string, revision int64) error {
+	entry, err := s.kv.Get(policyKeyPrefix + id)
+	if errors.Is(err, nats.ErrKeyNotFound) {
+		return fmt.Errorf("%w: %s", engine.ErrPolicyNotFound, id)
+	}
+	if err != nil {
+		return fmt.Errorf("error loading policy %s: %w", id, err)
+	}
+	var p Policy
+	if err := proto.Unmarshal(entry.Value(), &p); err != nil {
+		return fmt.Errorf("error parsing stored policy %s: %w", id, err)
+	}
+	if p.Revision != revision {
+		return fmt.Errorf("%w: policy %s is stored at revision %d, expected %d", engine.ErrRevisionConflict, id, p.Revision, revision)
+	}
+	return s.deletePolicyEntry(id, entry.Revision())
+}
+
+// deletePolicyEntry deletes a policy if its definition is still the one at
+// sequence in the bucket.
+func (s *PolicyStore) deletePolicyEntry(id string, sequence uint64) error {
+	if err := s.kv.Delete(policyKeyPrefix+id, nats.LastRevision(sequence)); err != nil {
+		var apiErr *nats.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode == nats.JSErrCodeStreamWrongLastSequence {
+			return fmt.Errorf("%w: policy %s was written concurrently", engine.ErrRevisionConflict, id)
+		}
+		return fmt.Errorf("error deleting policy %s: %w", id, err)
+	}
+	return nil
+}
+
+// SaveRevision stores a revision of a policy, replacing it if it exists.
+func (s *PolicyStore) SaveRevision(rev *PolicyRevision) error {
+	return s.writeRevision(rev, s.kv.Put)
+}
+
+// CreateRevision stores a new revision of a policy. It fails if the revision
+// already exists, which happens when two instances write the same policy at
+// the same time.
+func (s *PolicyStore) CreateRevision(rev *PolicyRevision) error {
+	return s.writeRevision(rev, s.kv.Create)
+}
+
+func (s *PolicyStore) writeRevision(rev *PolicyRevision, write func(string, []byte) (uint64, error)) error {
+	data, err := proto.Marshal(rev)
+	if err != nil {
+		return fmt.Errorf("error serializing revision %d of policy %s: %w", rev.Revision, rev.PolicyId, err)
+	}
+	if _, err := write(revisionKey(rev.PolicyId, rev.Revision), data); err != nil {
+		return fmt.Errorf("error storing revision %d of policy %s: %w", rev.Revision, rev.PolicyId, err)
+	}
+	return nil
+}
+
+func revisionKey(policyID string, revision int64) string {
+	return revisionKeyPrefix + policyID + "." + strconv.FormatInt(revision, 10)
+}
+
+func (s *PolicyStore) SaveDescriptorSet(name string, set *descriptorpb.FileDescriptorSet) error {
+	data, err := proto.Marshal(set)
+	if err != nil {
+		return fmt.Errorf("error serializing descriptor set %s: %w", name, err)
+	}
+	if _, err := s.kv.Put(descriptorKeyPrefix+name, data); err != nil {
+		return fmt.Errorf("error storing descriptor set %s: %w", name, err)
+	}
+	return nil
+}
+
+func (s *PolicyStore) DeleteDescriptorSet(name string) error {
+	if err := s.kv.Delete(descriptorKeyPrefix + name); err != nil && !errors.Is(err, nats.ErrKeyNotFound) {
+		return fmt.Errorf("error deleting descriptor set %s: %w", name, err)
+	}
+	return nil
+}
+
+// LoadDescriptorSets returns the stored descriptor sets by name.
+func (s *PolicyStore) LoadDescriptorSets() (map[string]*descriptorpb.FileDescriptorSet, error) {
+	entries, err := s.entries(descriptorKeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	sets := make(map[string]*descriptorpb.FileDescriptorSet, len(entries))
+	for _, entry := range entries {
+		name := strings.TrimPrefix(entry.Key(), descriptorKeyPrefix)
+		var set descriptorpb.FileDescriptorSet
+		if err := proto.Unmarshal(entry.Value(), &set); err != nil {
+			return nil, fmt.Errorf("error parsing stored descriptor set %s: %w", name, err)
+		}
+		sets[name] = &set
+	}
+	return sets, nil
+}
+
+func (s *PolicyStore) SaveBinding(b *InputBinding) error {
+	data, err := proto.Marshal(b)
+	if err != nil {
+		return fmt.Errorf("error serializing input binding %s: %w", b.Subject, err)
+	}
+	if _, err := s.kv.Put(bindingKeyPrefix+b.Subject, data); err != nil {
+		return fmt.Errorf("error storing input binding %s: %w", b.Subject, err)
+	}
+	return nil
+}
+
+func (s *PolicyStore) DeleteBinding(subject string) error {
+	if err := s.kv.Delete(bindingKeyPrefix + subject); err != nil && !errors.Is(err, nats.ErrKeyNotFound) {
+		return fmt.Errorf("error deleting input binding %s: %w", subject, err)
+	}
+	return nil
+}
+
+func (s *PolicyStore) LoadBindings() ([]*InputBinding, error) {
+	entries, err := s.entries(bindingKeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	bindings := make([]*InputBinding, 0, len(entries))
+	for _, entry := range entries {
+		var b InputBinding
+		if err := proto.Unmarshal(entry.Value(), &b); err != nil {
+			return nil, fmt.Errorf("error parsing stored input binding %s: %w", strings.TrimPrefix(entry.Key(), bindingKeyPrefix), err)
+		}
+		bindings = append(bindings, &b)
+	}
+	return bindings, nil
+}
+
+func (s *PolicyStore) SaveCalendar(c *Calendar) error {
+	data, err := proto.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("error serializing calendar %s: %w", c.Name, err)
+	}
+	if _, err := s.kv.Put(calendarKeyPrefix+c.Name, data); err != nil {
+		return fmt.Errorf("error storing calendar %s: %w", c.Name, err)
+	}
+	return nil
+}
+
+func (s *PolicyStore) DeleteCalendar(name string) error {
+	if err := s.kv.Delete(calendarKeyPrefix + name); err != nil && !errors.Is(err, nats.ErrKeyNotFound) {
+		return fmt.Errorf("error deleting calendar %s: %w", name, err)
+	}
+	return nil
+}
+
+func (s *PolicyStore) LoadCalendars() ([]*Calendar, error) {
+	entries, err := s.entries(calendarKeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	calendars := make([]*Calendar, 0, len(entries))
+	for _, entry := range entries {
+		var c Calendar
+		if err := proto.Unmarshal(entry.Value(), &c); err != nil {
+			return nil, fmt.Errorf("error parsing stored calendar %s: %w", strings.TrimPrefix(entry.Key(), calendarKeyPrefix), err)
+		}
+		calendars = append(calendars, &c)
+	}
+	return calendars, nil
+}
+
+func (s *PolicyStore) LoadRevisions() ([]*PolicyRevision, error) {
+	entries, err := s.entries(revisionKeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := make([]*PolicyRevision, 0, len(entries))
+	for _, entry := range entries {
+		var rev PolicyRevision
+		if err := proto.Unmarshal(entry.Value(), &rev); err != nil {
+			return nil, fmt.Errorf("error parsing stored revision %s: %w", strings.TrimPrefix(entry.Key(), revisionKeyPrefix), err)
+		}
+		revisions = append(revisions, &rev)
+	}
+	return revisions, nil
+}
+
+func (s *PolicyStore) LoadPolicies() ([]*Policy, error) {
+	entries, err := s.entries(policyKeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make([]*Policy, 0, len(entries))
+	for _, entry := range entries {
+		var p Policy
+		if err := proto.Unmarshal(entry.Value(), &p); err != nil {
+			return nil, fmt.Errorf("error parsing stored policy %s: %w", strings.TrimPrefix(entry.Key(), policyKeyPrefix), err)
+		}
+		policies = append(policies, &p)
+	}
+	return policies, nil
+}
+
+// Restore compiles the stored policies and loads them in the rule engine,
+// together with their revision history, the descriptor sets declaring their
+// input types, the input bindings and the calendars. A policy that no longer
+// compiles is logged and skipped, so that a single broken definition does not
+// prevent the engine from starting.
+func (s *PolicyStore) Restore(ruleEngine *engine.RuleEngine) (int, error) {
+	sets, err := s.LoadDescriptorSets()
+	if err != nil {
+		return 0, err
+	}
+	for name, set := range sets {
+		if _, err := ruleEngine.RegisterDescriptorSet(name, set); err != nil {
+			slog.Error("Error restoring stored descriptor set", "name", name, "error", err)
+		}
+	}
+
+	bindings, err := s.LoadBindings()
+	if err != nil {
+		return 0, err
+	}
+	for _, b := range bindings {
+		if err := ruleEngine.SetBinding(convertProtoToModelBinding(b)); err != nil {
+			slog.Error("Error restoring stored input binding", "subject", b.Subject, "error", err)
+		}
+	}
+
+	calendars, err := s.LoadCalendars()
+	if err != nil {
+		return 0, err
+	}
+	for _, c := range calendars {
+		if err := ruleEngine.SetCalendar(convertProtoToModelCalendar(c)); err != nil {
+			slog.Error("Error restoring stored calendar", "name", c.Name, "error", err)
+		}
+	}
+
+	revisions, err := s.LoadRevisions()
+	if err != nil {
+		return 0, err
+	}
+	for _, rev := range revisions {
+		modelRev, err := convertProtoToModelRevision(rev)
+		if err != nil {
+			slog.Error("Error restoring stored revision", "policy_id", rev.PolicyId, "revision", rev.Revision, "error", err)
+			continue
+		}
+		ruleEngine.RecordRevision(modelRev)
+	}
+
+	policies, err := s.LoadPolicies()
+	if err != nil {
+		return 0, err
+	}
+
+	loaded := 0
+	for _, p := range policies {
+		policy, err := convertProtoToModelPolicy(p)
+		if err == nil {
+			err = ruleEngine.LoadPolicy(policy)
+		}
+		if err != nil {
+			slog.Error("Error restoring stored policy", "policy_id", p.Id, "error", err)
+			continue
+		}
+		loaded++
+	}
+	return loaded, nil
+}
+
+// Watch keeps the rule engine in sync with the bucket: every revision and
+// every put or delete of a policy, made by this or any other instance, is
+// applied to the engine. It returns once the current content of the bucket
+// has been loaded, and the engine keeps following the bucket until the
+// watcher is stopped.
+func (s *PolicyStore) Watch(ruleEngine *engine.RuleEngine) (nats.KeyWatcher, error) {
+	watcher, err := s.kv.WatchAll()
+	if err != nil {
+		return nil, fmt.Errorf("error watching key-value bucket %s: %w", s.kv.Bucket(), err)
+	}
+
+	updates := watcher.Updates()
+	var initial []nats.KeyValueEntry
+	for entry := range updates {
+		// A nil entry marks the end of the initial values
+		if entry == nil {
+			break
+		}
+		initial = append(initial, entry)
+	}
+	sort.SliceStable(initial, func(i, j int) bool {
+		return entryRank(initial[i].Key()) < entryRank(initial[j].Key())
+	})
+	for _, entry := range initial {
+		applyEntry(ruleEngine, entry)
+	}
+
+	go func() {
+		for entry := range updates {
+			if entry != nil {
+				applyEntry(ruleEngine, entry)
+			}
+		}
+	}()
+
+	return watcher, nil
+}
+
+func entryRank(key string) int {
+	for i, prefix := range entryOrder {
+		if strings.HasPrefix(key, prefix) {
+			return i
+		}
+	}
+	return len(entryOrder)
+}
+
+func applyEntry(ruleEngine *engine.RuleEngine, entry nats.KeyValueEntry) {
+	switch {
+	case strings.HasPrefix(entry.Key(), policyKeyPrefix):
+		applyPolicyEntry(ruleEngine, entry)
+	case strings.HasPrefix(entry.Key(), revisionKeyPrefix):
+		applyRevisionEntry(ruleEngine, entry)
+	case strings.HasPrefix(entry.Key(), descriptorKeyPrefix):
+		applyDescriptorEntry(ruleEngine, entry)
+	case strings.HasPrefix(entry.Key(), bindingKeyPrefix):
+		applyBindingEntry(ruleEngine, entry)
+	case strings.HasPrefix(entry.Key(), calendarKeyPrefix):
+		applyCalendarEntry(ruleEngine, entry)
+	}
+}
+
+// applyCalendarEntry sets or removes a calendar.
+func applyCalendarEntry(ruleEngine *engine.RuleEngine, entry nats.KeyValueEntry) {
+	name := strings.TrimPrefix(entry.Key(), calendarKeyPrefix)
+
+	switch entry.Operation() {
+	case nats.KeyValuePut:
+		var c Calendar
+		if err := proto.Unmarshal(entry.Value(), &c); err != nil {
+			slog.Error("Error parsing watched calendar", "name", name, "error", err)
+			return
+		}
+		if err := ruleEngine.SetCalendar(convertProtoToModelCalendar(&c)); err != nil {
+			slog.Error("Error applying watched calendar", "name", name, "error", err)
+			return
+		}
+		slog.Debug("Watched calendar applied", "name", name, "revision", entry.Revision())
+	case nats.KeyValueDelete, nats.KeyValuePurge:
+		if err := ruleEngine.RemoveCalendar(name); err != nil {
+			slog.Debug("Watched calendar deletion ignored", "name", name, "error", err)
+			return
+		}
+		slog.Debug("Watched calendar deleted", "name", name, "revision", entry.Revision())
+	}
+}
+
+// applyBindingEntry sets or removes an input binding. Bindings are written
+// after the descriptor sets declaring their message types.
+func applyBindingEntry(ruleEngine *engine.RuleEngine, entry nats.KeyValueEntry) {
+	subject := strings.TrimPrefix(entry.Key(), bindingKeyPrefix)
+
+	switch entry.Operation() {
+	case nats.KeyValuePut:
+		var b InputBinding
+		if err := proto.Unmarshal(entry.Value(), &b); err != nil {
+			slog.Error("Error parsing watched input binding", "subject", subject, "error", err)
+			return
+		}
+		if err := ruleEngine.SetBinding(convertProtoToModelBinding(&b)); err != nil {
+			slog.Error("Error applying watched input binding", "subject", subject, "error", err)
+			return
+		}
+		slog.Debug("Watched input binding applied", "subject", subject, "revision", entry.Revision())
+	case nats.KeyValueDelete, nats.KeyValuePurge:
+		if err := ruleEngine.RemoveBinding(subject); err != nil {
+			slog.Debug("Watched input binding deletion ignored", "subject", subject, "error", err)
+			return
+		}
+		slog.Debug("Watched input binding deleted", "subject", subject, "revision", entry.Revision())
+	}
+}
+
+// applyDescriptorEntry registers or removes a descriptor set. Descriptor sets
+// are written before the policies that use them.
+func applyDescriptorEntry(ruleEngine *engine.RuleEngine, entry nats.KeyValueEntry) {
+	name := strings.TrimPrefix(entry.Key(), descriptorKeyPrefix)
+
+	switch entry.Operation() {
+	case nats.KeyValuePut:
+		var set descriptorpb.FileDescriptorSet
+		if err := proto.Unmarshal(entry.Value(), &set); err != nil {
+			slog.Error("Error parsing watched descriptor set", "name", name, "error", err)
+			return
+		}
+		if _, err := ruleEngine.RegisterDescriptorSet(name, &set); err != nil {
+			slog.Error("Error applying watched descriptor set", "name", name, "error", err)
+			return
+		}
+		slog.Debug("Watched descriptor set applied", "name", name, "revision", entry.Revision())
+	case nats.KeyValueDelete, nats.KeyValuePurge:
+		if err := ruleEngine.RemoveDescriptorSet(name); err != nil {
+			slog.Debug("Watched descriptor set deletion ignored", "name", name, "error", err)
+			return
+		}
+		slog.Debug("Watched descriptor set deleted", "name", name, "revision", entry.Revision())
+	}
+}
+
+// applyRevisionEntry records a revision in the history. Revisions are always
+// written before the policy they make current, so the engine knows the
+// revision number when the policy is applied.
+func applyRevisionEntry(ruleEngine *engine.RuleEngine, entry nats.KeyValueEntry) {
+	if entry.Operation() != nats.KeyValuePut {
+		return
+	}
+	var rev PolicyRevision
+	if err := proto.Unmarshal(entry.Value(), &rev); err != nil {
+		slog.Error("Error parsing watched revision", "key", entry.Key(), "error", err)
+		return
+	}
+	modelRev, err := convertProtoToModelRevision(&rev)
+	if err != nil {
+		slog.Error("Error applying watched revision", "key", entry.Key(), "error", err)
+		return
+	}
+	ruleEngine.RecordRevision(modelRev)
+}
+
+func applyPolicyEntry(ruleEngine *engine.RuleEngine, entry nats.KeyValueEntry) {
+	id := strings.TrimPrefix(entry.Key(), policyKeyPrefix)
+
+	switch entry.Operation() {
+	case nats.KeyValuePut:
+		var p Policy
+		if err := proto.Unmarshal(entry.Value(), &p); err != nil {
+			slog.Error("Error parsing watched policy", "policy_id", id, "error", err)
+			return
+		}
+		policy, err := convertProtoToModelPolicy(&p)
+		if err == nil {
+			err = ruleEngine.LoadPolicy(policy)
+		}
+		if err != nil {
+			slog.Error("Error applying watched policy", "policy_id", id, "error", err)
+			return
+		}
+		slog.Debug("Watched policy applied", "policy_id", id, "revision", entry.Revision())
+	case nats.KeyValueDelete, nats.KeyValuePurge:
+		// The policy may be already missing if it was deleted before this
+		// instance started
+		if err := ruleEngine.DeletePolicy(id); err != nil {
+			slog.Debug("Watched policy deletion ignored", "policy_id", id, "error", err)
+			return
+		}
+		slog.Debug("Watched policy deleted", "policy_id", id, "revision", entry.Revision())
+	}
+}
+
+// entries returns the current values of all the keys starting with prefix.
+func (s *PolicyStore) entries(prefix string) ([]nats.KeyValueEntry, error) {
+	watcher, err := s.kv.Watch(prefix+">", nats.IgnoreDeletes())
+	if err != nil {
+		return nil, fmt.Errorf("error reading key-value bucket %s: %w", s.kv.Bucket(), err)
+	}
+	defer watcher.Stop()
+
+	var entries []nats.KeyValueEntry
+	for entry := range watcher.Updates() {
+		// A nil entry marks the end of the initial values
+		if entry == nil {
+			break
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
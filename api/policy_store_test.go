@@ -0,0 +1,207 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/sandrolain/rules/engine"
+	"github.com/sandrolain/rules/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPolicy(id, expression string) models.Policy {
+	return models.Policy{
+		ID:         id,
+		Name:       id,
+		Rules:      []models.Rule{{Name: "Amount", Expression: expression}},
+		Thresholds: []models.Threshold{{ID: "high", Value: 100}},
+	}
+}
+
+func snapshotHash(t *testing.T, ruleEngine *engine.RuleEngine) string {
+	t.Helper()
+	hash, err := policySetHash(ruleEngine.Snapshot())
+	require.NoError(t, err)
+	return hash
+}
+
+func TestPolicyStore_Restore(t *testing.T) {
+	nc, js := runJetStream(t)
+	h := newTestHandler(t, nc, js, SyncLocal)
+
+	for _, p := range []models.Policy{testPolicy("payments", "input.amount"), testPolicy("refunds", "input.amount / 2")} {
+		_, err := h.writePolicy(p, engine.WriteOptions{Author: "alice"})
+		require.NoError(t, err)
+	}
+	_, err := h.writePolicy(testPolicy("payments", "input.amount * 2"), engine.WriteOptions{})
+	require.NoError(t, err)
+	_, err = h.writePolicy(testPolicy("chargebacks", "1"), engine.WriteOptions{})
+	require.NoError(t, err)
+	require.NoError(t, h.removePolicy("chargebacks", engine.WriteOptions{}))
+
+	replica, err := engine.NewRuleEngine()
+	require.NoError(t, err)
+	loaded, err := h.store.Restore(replica)
+	require.NoError(t, err)
+	assert.Equal(t, 2, loaded)
+
+	payments, err := replica.GetPolicy("payments")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), payments.Revision)
+	assert.Equal(t, "input.amount * 2", payments.Rules[0].Expression)
+	revisions, err := replica.ListRevisions("payments")
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, "alice", revisions[0].Author)
+	_, err = replica.GetPolicy("chargebacks")
+	assert.ErrorIs(t, err, engine.ErrPolicyNotFound)
+
+	assert.Equal(t, snapshotHash(t, h.ruleEngine), snapshotHash(t, replica))
+}
+
+func TestPolicyStore_Watch(t *testing.T) {
+	nc, js := runJetStream(t)
+	h := newTestHandler(t, nc, js, SyncWatch)
+	_, err := h.writePolicy(testPolicy("payments", "input.amount"), engine.WriteOptions{})
+	require.NoError(t, err)
+
+	// The handler does not apply its writes in watch mode
+	_, err = h.ruleEngine.GetPolicy("payments")
+	assert.ErrorIs(t, err, engine.ErrPolicyNotFound)
+
+	replicas := []*engine.RuleEngine{h.ruleEngine}
+	other, err := engine.NewRuleEngine()
+	require.NoError(t, err)
+	replicas = append(replicas, other)
+	for _, replica := range replicas {
+		watcher, err := h.store.Watch(replica)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = watcher.Stop() })
+
+		// The current content of the bucket is loaded before Watch returns
+		_, err = replica.GetPolicy("payments")
+		require.NoError(t, err)
+	}
+
+	inSync := func(check func(*engine.RuleEngine) bool) {
+		t.Helper()
+		for _, replica := range replicas {
+			assert.Eventually(t, func() bool { return check(replica) }, 5*time.Second, 10*time.Millisecond)
+		}
+		assert.Equal(t, snapshotHash(t, replicas[0]), snapshotHash(t, replicas[1]))
+	}
+
+	t.Run("Apply", func(t *testing.T) {
+		_, err := h.writePolicy(testPolicy("payments", "input.amount * 2"), engine.WriteOptions{})
+		require.NoError(t, err)
+		_, err = h.writePolicy(testPolicy("refunds", "input.amount"), engine.WriteOptions{})
+		require.NoError(t, err)
+
+		inSync(func(replica *engine.RuleEngine) bool {
+			payments, err := replica.GetPolicy("payments")
+			_, refundsErr := replica.GetPolicy("refunds")
+			return err == nil && payments.Revision == 2 && refundsErr == nil
+		})
+		revisions, err := other.ListRevisions("payments")
+		require.NoError(t, err)
+		assert.Len(t, revisions, 2)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		require.NoError(t, h.removePolicy("refunds", engine.WriteOptions{}))
+
+		inSync(func(replica *engine.RuleEngine) bool {
+			_, err := replica.GetPolicy("refunds")
+			return err != nil
+		})
+		_, err := other.GetPolicy("payments")
+		assert.NoError(t, err)
+	})
+}
+
+func TestNatsHandler_WatchRevisionConflict(t *testing.T) {
+	nc, js := runJetStream(t)
+	first := newTestHandler(t, nc, js, SyncWatch)
+	second := newTestHandler(t, nc, js, SyncWatch)
+
+	// Neither engine watches the store, so both prepare the first revision
+	_, err := first.writePolicy(testPolicy("payments", "input.amount"), engine.WriteOptions{})
+	require.NoError(t, err)
+	_, err = second.writePolicy(testPolicy("payments", "input.amount * 2"), engine.WriteOptions{})
+	assert.ErrorIs(t, err, engine.ErrRevisionConflict)
+	assert.Equal(t, ErrorCode_ERROR_CODE_CONFLICT, errorCode(err))
+
+	// The losing write did not replace the stored policy
+	policies, err := first.store.LoadPolicies()
+	require.NoError(t, err)
+	require.Len(t, policies, 1)
+	assert.Equal(t, "input.amount", policies[0].Rules[0].Expression)
+}
+
+func TestNatsHandler_WatchDeleteConflict(t *testing.T) {
+	nc, js := runJetStream(t)
+	first := newTestHandler(t, nc, js, SyncWatch)
+	second := newTestHandler(t, nc, js, SyncWatch)
+	_, err := first.writePolicy(testPolicy("payments", "input.amount"), engine.WriteOptions{})
+	require.NoError(t, err)
+
+	watchers := make([]nats.KeyWatcher, 0, 2)
+	for _, h := range []*NatsHandler{first, second} {
+		watcher, err := h.store.Watch(h.ruleEngine)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = watcher.Stop() })
+		watchers = append(watchers, watcher)
+	}
+
+	// The second engine stops following the store and misses revision 2
+	require.NoError(t, watchers[1].Stop())
+	_, err = first.writePolicy(testPolicy("payments", "input.amount * 2"), engine.WriteOptions{})
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		payments, err := first.ruleEngine.GetPolicy("payments")
+		return err == nil && payments.Revision == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	t.Run("Stale engine", func(t *testing.T) {
+		err := second.removePolicy("payments", engine.WriteOptions{})
+		assert.ErrorIs(t, err, engine.ErrRevisionConflict)
+		assert.Equal(t, ErrorCode_ERROR_CODE_CONFLICT, errorCode(err))
+	})
+
+	t.Run("Stale expected revision", func(t *testing.T) {
+		err := second.removePolicy("payments", engine.WriteOptions{ExpectedRevision: 1})
+		assert.ErrorIs(t, err, engine.ErrRevisionConflict)
+	})
+
+	// The policy was not deleted by the losing requests
+	policies, err := first.store.LoadPolicies()
+	require.NoError(t, err)
+	require.Len(t, policies, 1)
+
+	t.Run("Current revision", func(t *testing.T) {
+		require.NoError(t, first.removePolicy("payments", engine.WriteOptions{ExpectedRevision: 2}))
+		assert.Eventually(t, func() bool {
+			_, err := first.ruleEngine.GetPolicy("payments")
+			return err != nil
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("Concurrent write", func(t *testing.T) {
+		_, err := first.writePolicy(testPolicy("refunds", "input.amount"), engine.WriteOptions{})
+		require.NoError(t, err)
+		entry, err := first.store.kv.Get(policyKeyPrefix + "refunds")
+		require.NoError(t, err)
+		assert.Eventually(t, func() bool {
+			_, err := first.ruleEngine.GetPolicy("refunds")
+			return err == nil
+		}, 5*time.Second, 10*time.Millisecond)
+		_, err = first.writePolicy(testPolicy("refunds", "input.amount * 2"), engine.WriteOptions{ExpectedRevision: 1})
+		require.NoError(t, err)
+
+		// The definition read before the write is no longer the stored one
+		err = first.store.deletePolicyEntry("refunds", entry.Revision())
+		assert.ErrorIs(t, err, engine.ErrRevisionConflict)
+	})
+}
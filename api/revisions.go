@@ -0,0 +1,159 @@
+package api
+
+import (
+	"log/slog"
+
+	"github.com/nats-io/nats.go"
+	"github.com/sandrolain/rules/engine"
+	"github.com/sandrolain/rules/models"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (h *NatsHandler) handleListRevisions(msg *nats.Msg) {
+	var req ListRevisionsRequest
+	if err := proto.Unmarshal(msg.Data, &req); err != nil {
+		slog.Error("Error unmarshalling ListRevisions request", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		slog.Error("Error validating ListRevisions request", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	revisions, err := h.ruleEngine.ListRevisions(req.PolicyId)
+	if err != nil {
+		slog.Error("Error listing revisions", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	resp := &ListRevisionsResponse{
+		Revisions: make([]*PolicyRevision, len(revisions)),
+	}
+	for i, rev := range revisions {
+		resp.Revisions[i] = convertModelToProtoRevision(rev, false)
+	}
+	if err := h.replyWithProto(msg, resp); err != nil {
+		slog.Error("Error sending response", "error", err)
+	}
+}
+
+func (h *NatsHandler) handleGetRevision(msg *nats.Msg) {
+	var req GetRevisionRequest
+	if err := proto.Unmarshal(msg.Data, &req); err != nil {
+		slog.Error("Error unmarshalling GetRevision request", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		slog.Error("Error validating GetRevision request", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	rev, err := h.ruleEngine.GetRevision(req.PolicyId, req.Revision)
+	if err != nil {
+		slog.Error("Error retrieving revision", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	resp := &GetRevisionResponse{
+		Revision: convertModelToProtoRevision(rev, true),
+	}
+	if err := h.replyWithProto(msg, resp); err != nil {
+		slog.Error("Error sending response", "error", err)
+	}
+}
+
+func (h *NatsHandler) handleRollbackPolicy(msg *nats.Msg) {
+	var req RollbackPolicyRequest
+	if err := proto.Unmarshal(msg.Data, &req); err != nil {
+		slog.Error("Error unmarshalling RollbackPolicy request", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		slog.Error("Error validating RollbackPolicy request", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	opts := engine.WriteOptions{
+		Author:           req.Author,
+		Note:             req.Note,
+		ExpectedRevision: req.ExpectedRevision,
+	}
+	rev, err := h.writeRevision(func() (models.PolicyRevision, error) {
+		return h.ruleEngine.PrepareRollback(req.PolicyId, req.Revision, opts)
+	})
+	if err != nil {
+		slog.Error("Error rolling back policy", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	slog.Info("Policy rolled back", "policy_id", req.PolicyId, "to_revision", req.Revision, "revision", rev.Revision)
+	resp := &RollbackPolicyResponse{Success: true, Revision: rev.Revision}
+	if err := h.replyWithProto(msg, resp); err != nil {
+		slog.Error("Error sending response", "error", err)
+	}
+}
+
+// convertModelToProtoRevision converts a revision; the policy definition is
+// included only if withPolicy is set.
+func convertModelToProtoRevision(rev models.PolicyRevision, withPolicy bool) *PolicyRevision {
+	p := &PolicyRevision{
+		PolicyId:  rev.Policy.ID,
+		Revision:  rev.Revision,
+		Timestamp: timestamppb.New(rev.Timestamp),
+		Author:    rev.Author,
+		Note:      rev.Note,
+	}
+	if withPolicy {
+		p.Policy = convertModelToProtoPolicy(rev.Policy)
+	}
+	return p
+}
+
+func convertProtoToModelRevision(p *PolicyRevision) (models.PolicyRevision, error) {
+	rev := models.PolicyRevision{
+		Revision:  p.Revision,
+		Timestamp: p.Timestamp.AsTime(),
+		Author:    p.Author,
+		Note:      p.Note,
+	}
+	if p.Policy != nil {
+		policy, err := convertProtoToModelPolicy(p.Policy)
+		if err != nil {
+			return models.PolicyRevision{}, err
+		}
+		rev.Policy = policy
+	}
+	rev.Policy.ID = p.PolicyId
+	return rev, nil
+}
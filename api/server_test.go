@@ -0,0 +1,45 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/nats-io/nats-server/v2/server"
+	natsserver "github.com/nats-io/nats-server/v2/test"
+	"github.com/nats-io/nats.go"
+	"github.com/sandrolain/rules/engine"
+	"github.com/stretchr/testify/require"
+)
+
+const testBucket = "RULES_POLICIES_TEST"
+
+// runJetStream starts an embedded NATS server with JetStream enabled and
+// returns a connection to it. Both are closed when the test ends.
+func runJetStream(t *testing.T) (*nats.Conn, nats.JetStreamContext) {
+	t.Helper()
+	opts := natsserver.DefaultTestOptions
+	opts.Port = server.RANDOM_PORT
+	opts.JetStream = true
+	opts.StoreDir = t.TempDir()
+	srv := natsserver.RunServer(&opts)
+	t.Cleanup(srv.Shutdown)
+
+	nc, err := nats.Connect(srv.ClientURL())
+	require.NoError(t, err)
+	t.Cleanup(nc.Close)
+	js, err := nc.JetStream()
+	require.NoError(t, err)
+	return nc, js
+}
+
+// newTestHandler returns a handler of a new engine, with a store in the test
+// bucket.
+func newTestHandler(t *testing.T, nc *nats.Conn, js nats.JetStreamContext, mode SyncMode) *NatsHandler {
+	t.Helper()
+	store, err := NewPolicyStore(js, testBucket)
+	require.NoError(t, err)
+	ruleEngine, err := engine.NewRuleEngine()
+	require.NoError(t, err)
+	h, err := NewNatsHandler(nc, ruleEngine, store, HandlerOptions{InstanceID: string(mode), SyncMode: mode})
+	require.NoError(t, err)
+	return h
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+
+	"github.com/bufbuild/protovalidate-go"
+	"github.com/nats-io/nats.go"
+	"github.com/sandrolain/rules/engine"
+	"google.golang.org/protobuf/proto"
+)
+
+func (h *NatsHandler) handleValidatePolicy(msg *nats.Msg) {
+	var req ValidatePolicyRequest
+	if err := proto.Unmarshal(msg.Data, &req); err != nil {
+		slog.Error("Error unmarshalling ValidatePolicy request", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	if req.Policy == nil {
+		err := errors.New("policy is required")
+		slog.Error("Error validating ValidatePolicy request", "error", err)
+		if err := h.replyWithError(msg, err); err != nil {
+			slog.Error("Error sending error response", "error", err)
+		}
+		return
+	}
+
+	// Constraint violations of the policy are reported as diagnostics too,
+	// so the editor gets all the problems of the policy in one response
+	var diagnostics []engine.Diagnostic
+	if err := h.validator.Validate(req.Policy); err != nil {
+		var validationErr *protovalidate.ValidationError
+		if !errors.As(err, &validationErr) {
+			slog.Error("Error validating ValidatePolicy request", "error", err)
+			if err := h.replyWithError(msg, err); err != nil {
+				slog.Error("Error sending error response", "error", err)
+			}
+			return
+		}
+		for _, violation := range validationErr.Violations {
+			diagnostics = append(diagnostics, engine.Diagnostic{
+				Field:    violation.GetFieldPath(),
+				Message:  violation.GetMessage(),
+				Severity: engine.SeverityError,
+			})
+		}
+	}
+
+	// Unknown enum values are violations too, so they are reported only if
+	// the validator did not report them already
+	policy, err := convertProtoToModelPolicy(req.Policy)
+	if err != nil {
+		if !engine.HasErrors(diagnostics) {
+			diagnostics = append(diagnostics, engine.Diagnostic{Message: err.Error(), Severity: engine.SeverityError})
+		}
+	} else {
+		diagnostics = append(diagnostics, h.ruleEngine.ValidatePolicy(policy)...)
+	}
+
+	resp := &ValidatePolicyResponse{
+		Valid:       !engine.HasErrors(diagnostics),
+		Diagnostics: make([]*Diagnostic, len(diagnostics)),
+	}
+	for i, d := range diagnostics {
+		resp.Diagnostics[i] = convertDiagnostic(d)
+	}
+	if err := h.replyWithProto(msg, resp); err != nil {
+		slog.Error("Error sending response", "error", err)
+	}
+}
+
+func convertDiagnostic(d engine.Diagnostic) *Diagnostic {
+	severity := DiagnosticSeverity_DIAGNOSTIC_SEVERITY_ERROR
+	if d.Severity == engine.SeverityWarning {
+		severity = DiagnosticSeverity_DIAGNOSTIC_SEVERITY_WARNING
+	}
+	return &Diagnostic{
+		Rule:     d.Rule,
+		Field:    d.Field,
+		Line:     int32(d.Line),
+		Column:   int32(d.Column),
+		Message:  d.Message,
+		Severity: severity,
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/nats-io/nats.go"
 	"github.com/sandrolain/rules/api"
 	"github.com/sandrolain/rules/engine"
+	"github.com/sandrolain/rules/models"
 )
 
 type App struct {
@@ -56,7 +57,33 @@ func (a *App) Run() error {
 		return err
 	}
 
-	natsHandler, err := api.NewNatsHandler(a.nc, a.ruleEngine)
+	store, err := api.NewPolicyStore(a.js, a.cfg.NatsPolicyBucket)
+	if err != nil {
+		return fmt.Errorf("error opening policy store: %w", err)
+	}
+
+	// Stored policies must be loaded before any input is evaluated
+	syncMode := api.SyncMode(a.cfg.PolicySyncMode)
+	var watcher nats.KeyWatcher
+	if syncMode == api.SyncWatch {
+		watcher, err = store.Watch(a.ruleEngine)
+		if err != nil {
+			return fmt.Errorf("error watching policies: %w", err)
+		}
+		a.logger.Info("Watching policies", "bucket", a.cfg.NatsPolicyBucket, "count", a.ruleEngine.Snapshot().Len())
+	} else {
+		loaded, err := store.Restore(a.ruleEngine)
+		if err != nil {
+			return fmt.Errorf("error restoring policies: %w", err)
+		}
+		a.logger.Info("Policies restored", "bucket", a.cfg.NatsPolicyBucket, "count", loaded)
+	}
+
+	natsHandler, err := api.NewNatsHandler(a.nc, a.ruleEngine, store, api.HandlerOptions{
+		InstanceID:   a.instanceID(),
+		SyncMode:     syncMode,
+		InputSubject: a.cfg.NatsInputSubject,
+	})
 	if err != nil {
 		return fmt.Errorf("error creating NATS handler: %w", err)
 	}
@@ -84,6 +111,12 @@ func (a *App) Run() error {
 		a.logger.Error("Error unsubscribing from NATS", "error", err)
 	}
 
+	if watcher != nil {
+		if err := watcher.Stop(); err != nil {
+			a.logger.Error("Error stopping policy watcher", "error", err)
+		}
+	}
+
 	if err := a.nc.Drain(); err != nil {
 		a.logger.Error("Error draining NATS connection", "error", err)
 	}
@@ -94,12 +127,26 @@ func (a *App) Run() error {
 	return nil
 }
 
+// instanceID identifies this instance in the policy set hash responses. It
+// defaults to the host name, which is unique for each replica in most
+// deployments.
+func (a *App) instanceID() string {
+	if a.cfg.InstanceID != "" {
+		return a.cfg.InstanceID
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		return hostname
+	}
+	return fmt.Sprintf("pid-%d", os.Getpid())
+}
+
 func (a *App) setupStreams() error {
 	streams := []struct {
 		name     string
 		subjects []string
 	}{
-		{a.cfg.NatsInputStream, []string{a.cfg.NatsInputSubject}},
+		// The subjects under the input subject can be bound to protobuf types
+		{a.cfg.NatsInputStream, []string{a.cfg.NatsInputSubject, a.cfg.NatsInputSubject + ".>"}},
 		{a.cfg.NatsOutputStream, []string{a.cfg.NatsOutputSubject}},
 	}
 
@@ -162,56 +209,111 @@ func equalStringSlices(a, b []string) bool {
 }
 
 func (a *App) setupInputSubscription() (*nats.Subscription, error) {
-	return a.js.Subscribe(a.cfg.NatsInputSubject, a.handleInput)
+	// Bound to the stream, to receive the input subject and all the subjects
+	// under it
+	return a.js.Subscribe("", a.handleInput, nats.BindStream(a.cfg.NatsInputStream))
+}
+
+// decodeInput decodes the payload of an input message. The payloads published
+// on a bound subject are binary protobuf messages of the bound type; all the
+// others are JSON objects.
+func (a *App) decodeInput(m *nats.Msg) (models.Input, error) {
+	binding, err := a.ruleEngine.GetBinding(m.Subject)
+	if err != nil {
+		return models.DecodeJSON(m.Data, a.cfg.InputNumbers == "exact")
+	}
+
+	msg, err := a.ruleEngine.DecodeMessage(binding.MessageType, m.Data)
+	if err != nil {
+		return models.Input{}, err
+	}
+	return models.MessageInput(msg), nil
 }
 
 func (a *App) handleInput(m *nats.Msg) {
-	var input map[string]interface{}
-	if err := json.Unmarshal(m.Data, &input); err != nil {
-		a.logger.Error("Error parsing input", "error", err)
+	input, err := a.decodeInput(m)
+	if err != nil {
+		a.logger.Error("Error parsing input", "error", err, "subject", m.Subject)
 		a.sendInputAck(m, false, "Error parsing input")
 		return
 	}
 
-	policies := a.ruleEngine.GetAllPolicies()
+	// Every policy is evaluated against the same snapshot, even if the
+	// policies are changed while this input is being processed
+	policySet := a.ruleEngine.Snapshot()
+	policies := policySet.GetAllPolicies()
 	results := make([]api.PolicyResult, 0, len(policies))
+	explainInput := m.Header.Get(api.ExplainHeader) == "true"
+	// The messages of the publish actions are published only once the
+	// results of every policy are, so that no action is seen for a decision
+	// that was not emitted
+	var messages []policyMessages
+	failed := false
 
 	for _, policy := range policies {
-		shouldExecute, err := policy.ShouldExecute(input)
-		if err != nil {
-			a.logger.Error("Error evaluating CEL expression", "error", err, "policy_id", policy.ID)
-			results = append(results, api.PolicyResult{PolicyId: policy.ID, ResultThreshold: "", Error: err.Error()})
-			break
-		}
+		var result models.PolicyResult
+		if explainInput || policy.Explain {
+			// The trace is reported even if the policy expression is false
+			result, err = policySet.ExplainPolicy(policy.ID, input)
+			if err != nil {
+				a.logger.Error("Error explaining policy", "error", err, "policy_id", policy.ID)
+				results = append(results, api.PolicyResult{PolicyId: policy.ID, ResultThreshold: "", Error: err.Error()})
+				failed = true
+				break
+			}
+			if !result.Trace.Executed {
+				results = append(results, api.PolicyResult{PolicyId: policy.ID, Trace: api.ConvertModelToProtoTrace(result.Trace)})
+				continue
+			}
+		} else {
+			var executed bool
+			result, executed, err = policySet.RunPolicy(policy.ID, input)
+			if err != nil {
+				a.logger.Error("Error evaluating policy", "error", err, "policy_id", policy.ID)
+				results = append(results, api.PolicyResult{PolicyId: policy.ID, ResultThreshold: "", Error: err.Error()})
+				failed = true
+				break
+			}
 
-		if !shouldExecute {
-			a.logger.Debug("Policy will not be executed for this input", "policy_id", policy.ID)
-			continue
+			if !executed {
+				a.logger.Debug("Policy will not be executed for this input", "policy_id", policy.ID)
+				continue
+			}
 		}
 
-		result, ruleResults, err := a.ruleEngine.EvaluatePolicy(policy.ID, input)
+		outputs, err := api.ConvertModelToProtoValues(result.Outputs)
 		if err != nil {
-			a.logger.Error("Error evaluating policy", "error", err, "policy_id", policy.ID)
+			a.logger.Error("Error converting policy outputs", "error", err, "policy_id", policy.ID)
 			results = append(results, api.PolicyResult{PolicyId: policy.ID, ResultThreshold: "", Error: err.Error()})
+			failed = true
 			break
 		}
 
-		apiRuleResults := make([]*api.RuleResult, len(ruleResults))
-		for i, rr := range ruleResults {
-			apiRuleResults[i] = &api.RuleResult{
-				Score:    rr.Score,
-				Stop:     rr.Stop,
-				Executed: rr.Executed,
-			}
+		apiRuleResults, err := api.ConvertModelToProtoRuleResults(policy.Rules, result.Rules)
+		if err != nil {
+			a.logger.Error("Error converting rule results", "error", err, "policy_id", policy.ID)
+			results = append(results, api.PolicyResult{PolicyId: policy.ID, ResultThreshold: "", Error: err.Error()})
+			failed = true
+			break
 		}
 
 		results = append(results, api.PolicyResult{
 			PolicyId:        policy.ID,
-			ResultThreshold: result,
+			ResultThreshold: result.Threshold,
+			Score:           result.Score,
+			Outcome:         result.Outcome,
+			Outcomes:        result.Outcomes,
+			Attributes:      result.Actions.Attributes,
+			Tags:            result.Actions.Tags,
+			Obligations:     api.ConvertModelToProtoObligations(result.Actions.Obligations),
+			Outputs:         outputs,
+			Reasons:         api.ConvertModelToProtoReasons(result.Reasons),
+			Trace:           api.ConvertModelToProtoTrace(result.Trace),
 			RuleResults:     apiRuleResults,
 		})
+		messages = append(messages, policyMessages{policyID: policy.ID, messages: result.Actions.Messages})
 
-		a.logger.Info("Policy result", "policy_id", policy.ID, "result", result, "rule_results", ruleResults)
+		a.logger.Info("Policy result", "policy_id", policy.ID, "result", result.Threshold, "score", result.Score, "outcome", result.Outcome, "rule_results", result.Rules)
 	}
 
 	resultsPtr := make([]*api.PolicyResult, len(results))
@@ -234,10 +336,33 @@ func (a *App) handleInput(m *nats.Msg) {
 		a.sendInputAck(m, false, "Error publishing results")
 		return
 	}
+	if !failed {
+		for _, pm := range messages {
+			a.publishMessages(pm.policyID, pm.messages)
+		}
+	}
 
 	a.sendInputAck(m, true, "Input processed successfully")
 }
 
+// policyMessages are the messages of the publish actions of a policy.
+type policyMessages struct {
+	policyID string
+	messages []models.Message
+}
+
+// publishMessages publishes the messages of the publish actions of a
+// threshold. They are published on core NATS, since their subjects are not
+// bound to the streams of the engine; an error is logged and does not fail
+// the evaluation.
+func (a *App) publishMessages(policyID string, messages []models.Message) {
+	for _, msg := range messages {
+		if err := a.nc.Publish(msg.Subject, msg.Data); err != nil {
+			a.logger.Error("Error publishing action message", "error", err, "policy_id", policyID, "subject", msg.Subject)
+		}
+	}
+}
+
 func (a *App) sendInputAck(m *nats.Msg, success bool, message string) {
 	ack := api.InputAck{
 		Success: success,
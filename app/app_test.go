@@ -0,0 +1,117 @@
+package app
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	natsserver "github.com/nats-io/nats-server/v2/test"
+	"github.com/nats-io/nats.go"
+	"github.com/sandrolain/rules/engine"
+	"github.com/sandrolain/rules/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestApp returns an app connected to an embedded NATS server with
+// JetStream enabled, with the output stream and a policy whose high
+// threshold publishes the decision to the alerts subject.
+func newTestApp(t *testing.T) *App {
+	t.Helper()
+	opts := natsserver.DefaultTestOptions
+	opts.Port = server.RANDOM_PORT
+	opts.JetStream = true
+	opts.StoreDir = t.TempDir()
+	srv := natsserver.RunServer(&opts)
+	t.Cleanup(srv.Shutdown)
+
+	nc, err := nats.Connect(srv.ClientURL())
+	require.NoError(t, err)
+	t.Cleanup(nc.Close)
+	js, err := nc.JetStream()
+	require.NoError(t, err)
+
+	ruleEngine, err := engine.NewRuleEngine()
+	require.NoError(t, err)
+	require.NoError(t, ruleEngine.AddPolicy(models.Policy{
+		ID:    "payments",
+		Rules: []models.Rule{{Name: "Amount", Expression: "input.amount"}},
+		Thresholds: []models.Threshold{
+			{ID: "low", Value: 0},
+			{ID: "high", Value: 100, Actions: []models.Action{{Type: models.ActionPublish, Subject: "alerts"}}},
+		},
+	}))
+
+	cfg := &Config{
+		NatsInputSubject:  "rules.engine.input",
+		NatsOutputSubject: "rules.engine.output",
+		NatsInputStream:   "RULES_INPUT",
+		NatsOutputStream:  "RULES_OUTPUT",
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	a := &App{cfg: cfg, logger: logger, nc: nc, js: js, ruleEngine: ruleEngine}
+	require.NoError(t, a.setupStreams())
+	return a
+}
+
+// subscribe collects, in order, the messages published to the subjects.
+func subscribe(t *testing.T, nc *nats.Conn, subjects ...string) chan *nats.Msg {
+	t.Helper()
+	ch := make(chan *nats.Msg, 16)
+	for _, subject := range subjects {
+		sub, err := nc.ChanSubscribe(subject, ch)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = sub.Unsubscribe() })
+	}
+	require.NoError(t, nc.Flush())
+	return ch
+}
+
+func received(t *testing.T, nc *nats.Conn, ch chan *nats.Msg) []string {
+	t.Helper()
+	require.NoError(t, nc.Flush())
+	var subjects []string
+	for {
+		select {
+		case msg := <-ch:
+			subjects = append(subjects, msg.Subject)
+		case <-time.After(100 * time.Millisecond):
+			return subjects
+		}
+	}
+}
+
+func TestApp_HandleInput_ActionMessages(t *testing.T) {
+	input := &nats.Msg{Subject: "rules.engine.input", Data: []byte(`{"amount": 150}`)}
+
+	t.Run("Published after the results", func(t *testing.T) {
+		a := newTestApp(t)
+		ch := subscribe(t, a.nc, a.cfg.NatsOutputSubject, "alerts")
+
+		a.handleInput(input)
+		assert.Equal(t, []string{a.cfg.NatsOutputSubject, "alerts"}, received(t, a.nc, ch))
+	})
+
+	t.Run("Not published if the results are not", func(t *testing.T) {
+		a := newTestApp(t)
+		ch := subscribe(t, a.nc, "alerts")
+		require.NoError(t, a.js.DeleteStream(a.cfg.NatsOutputStream))
+
+		a.handleInput(input)
+		assert.Empty(t, received(t, a.nc, ch))
+	})
+
+	t.Run("Not published if a policy fails", func(t *testing.T) {
+		a := newTestApp(t)
+		require.NoError(t, a.ruleEngine.AddPolicy(models.Policy{
+			ID:    "refunds",
+			Rules: []models.Rule{{Name: "Refund", Expression: "input.refund"}},
+		}))
+		ch := subscribe(t, a.nc, a.cfg.NatsOutputSubject, "alerts")
+
+		a.handleInput(input)
+		assert.Equal(t, []string{a.cfg.NatsOutputSubject}, received(t, a.nc, ch))
+	})
+}
@@ -14,6 +14,10 @@ type Config struct {
 	NatsOutputSubject string `env:"NATS_OUTPUT_SUBJECT" envDefault:"rules.engine.output" validate:"required"`
 	NatsInputStream   string `env:"NATS_INPUT_STREAM" envDefault:"RULES_INPUT" validate:"required"`
 	NatsOutputStream  string `env:"NATS_OUTPUT_STREAM" envDefault:"RULES_OUTPUT" validate:"required"`
+	NatsPolicyBucket  string `env:"NATS_POLICY_BUCKET" envDefault:"RULES_POLICIES" validate:"required"`
+	PolicySyncMode    string `env:"POLICY_SYNC_MODE" envDefault:"local" validate:"oneof=local watch"`
+	InputNumbers      string `env:"INPUT_NUMBERS" envDefault:"float" validate:"oneof=float exact"`
+	InstanceID        string `env:"INSTANCE_ID"`
 	LogLevel          string `env:"LOG_LEVEL" envDefault:"info" validate:"oneof=debug info warn error"`
 }
 
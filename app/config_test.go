@@ -25,6 +25,9 @@ func TestLoadConfig(t *testing.T) {
 				NatsOutputSubject: "rules.engine.output",
 				NatsInputStream:   "RULES_INPUT",
 				NatsOutputStream:  "RULES_OUTPUT",
+				NatsPolicyBucket:  "RULES_POLICIES",
+				PolicySyncMode:    "local",
+				InputNumbers:      "float",
 				LogLevel:          "info",
 			},
 			expectError: false,
@@ -37,6 +40,10 @@ func TestLoadConfig(t *testing.T) {
 				"NATS_OUTPUT_SUBJECT": "custom.output",
 				"NATS_INPUT_STREAM":   "CUSTOM_INPUT",
 				"NATS_OUTPUT_STREAM":  "CUSTOM_OUTPUT",
+				"NATS_POLICY_BUCKET":  "CUSTOM_POLICIES",
+				"POLICY_SYNC_MODE":    "watch",
+				"INPUT_NUMBERS":       "exact",
+				"INSTANCE_ID":         "instance-1",
 				"LOG_LEVEL":           "debug",
 			},
 			expected: &Config{
@@ -45,6 +52,10 @@ func TestLoadConfig(t *testing.T) {
 				NatsOutputSubject: "custom.output",
 				NatsInputStream:   "CUSTOM_INPUT",
 				NatsOutputStream:  "CUSTOM_OUTPUT",
+				NatsPolicyBucket:  "CUSTOM_POLICIES",
+				PolicySyncMode:    "watch",
+				InputNumbers:      "exact",
+				InstanceID:        "instance-1",
 				LogLevel:          "debug",
 			},
 			expectError: false,
@@ -56,6 +67,20 @@ func TestLoadConfig(t *testing.T) {
 			},
 			expectError: true,
 		},
+		{
+			name: "Invalid policy sync mode",
+			envVars: map[string]string{
+				"POLICY_SYNC_MODE": "invalid",
+			},
+			expectError: true,
+		},
+		{
+			name: "Invalid input numbers mode",
+			envVars: map[string]string{
+				"INPUT_NUMBERS": "decimal",
+			},
+			expectError: true,
+		},
 		{
 			name: "Invalid log level",
 			envVars: map[string]string{
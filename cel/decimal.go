@@ -0,0 +1,392 @@
+package cel
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/operators"
+	"github.com/google/cel-go/common/overloads"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+	"github.com/google/cel-go/interpreter"
+)
+
+// DecimalType is the CEL type of the exact decimal numbers. Its traits let the
+// standard operators dispatch to the methods of Decimal.
+var DecimalType = cel.OpaqueType("decimal").WithTraits(traits.AdderType | traits.SubtractorType |
+	traits.MultiplierType | traits.DividerType | traits.NegatorType | traits.ComparerType)
+
+// maxDecimalDigits is the number of fractional digits a decimal is formatted
+// with when it has no finite decimal representation, like the result of 1/3.
+const maxDecimalDigits = 34
+
+// Decimal is an exact decimal number. The results of additions,
+// subtractions and multiplications are exact; divisions are exact too, and
+// are rounded only when formatted or converted.
+type Decimal struct {
+	r *big.Rat
+}
+
+// maxDecimalExponent bounds the exponent of the decimals in scientific
+// notation, and the digits they are rounded to, since the size of a decimal
+// grows with them.
+const maxDecimalExponent = 1000
+
+// ParseDecimal parses a decimal number, in plain or scientific notation.
+func ParseDecimal(s string) (Decimal, error) {
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		exp, err := strconv.Atoi(s[i+1:])
+		if err != nil || exp < -maxDecimalExponent || exp > maxDecimalExponent {
+			return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+		}
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok || strings.ContainsAny(s, "/xXpP") {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	return Decimal{r}, nil
+}
+
+// NewDecimalFromInt returns the decimal of an integer.
+func NewDecimalFromInt(i int64) Decimal {
+	return Decimal{new(big.Rat).SetInt64(i)}
+}
+
+// NewDecimalFromFloat returns the decimal with the shortest representation
+// that converts back to f, so that 0.1 is the decimal 0.1.
+func NewDecimalFromFloat(f float64) (Decimal, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Decimal{}, fmt.Errorf("cannot convert %v to a decimal", f)
+	}
+	return ParseDecimal(strconv.FormatFloat(f, 'g', -1, 64))
+}
+
+// Rat returns the value of the decimal as a rational number.
+func (d Decimal) Rat() *big.Rat {
+	return new(big.Rat).Set(d.r)
+}
+
+// Float64 returns the nearest float64 to the decimal.
+func (d Decimal) Float64() float64 {
+	f, _ := d.r.Float64()
+	return f
+}
+
+// String formats the decimal with the digits it has, or with
+// maxDecimalDigits fractional digits if it has no finite representation.
+func (d Decimal) String() string {
+	digits, exact := d.r.FloatPrec()
+	if !exact {
+		return strings.TrimRight(strings.TrimRight(d.r.FloatString(maxDecimalDigits), "0"), ".")
+	}
+	return d.r.FloatString(digits)
+}
+
+// MarshalJSON writes the decimal as a JSON number.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// Round rounds the decimal to the given number of fractional digits, with
+// halves rounded away from zero, or to even if halfEven is set.
+func (d Decimal) Round(places int64, halfEven bool) Decimal {
+	return Decimal{roundRat(d.r, places, func(q, rem *big.Int, den *big.Int) bool {
+		// rem is the remainder of the division of the scaled value by den,
+		// with the sign of the value
+		twice := new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2))
+		switch twice.Cmp(den) {
+		case 1:
+			return true
+		case 0:
+			return !halfEven || q.Bit(0) == 1
+		}
+		return false
+	})}
+}
+
+// Truncate drops the fractional digits beyond the given number.
+func (d Decimal) Truncate(places int64) Decimal {
+	return Decimal{roundRat(d.r, places, func(_, _, _ *big.Int) bool { return false })}
+}
+
+// roundRat scales r by 10^places, truncates it toward zero, moves the result
+// away from zero if up says so, and scales it back.
+func roundRat(r *big.Rat, places int64, up func(q, rem, den *big.Int) bool) *big.Rat {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(abs64(places)), nil)
+	scaled := new(big.Rat).Set(r)
+	if places >= 0 {
+		scaled.Mul(scaled, new(big.Rat).SetInt(scale))
+	} else {
+		scaled.Quo(scaled, new(big.Rat).SetInt(scale))
+	}
+
+	q, rem := new(big.Int).QuoRem(scaled.Num(), scaled.Denom(), new(big.Int))
+	if rem.Sign() != 0 && up(q, rem, scaled.Denom()) {
+		q.Add(q, big.NewInt(int64(rem.Sign())))
+	}
+
+	result := new(big.Rat).SetInt(q)
+	if places >= 0 {
+		return result.Quo(result, new(big.Rat).SetInt(scale))
+	}
+	return result.Mul(result, new(big.Rat).SetInt(scale))
+}
+
+func abs64(i int64) int64 {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
+
+func (d Decimal) ConvertToNative(typeDesc reflect.Type) (any, error) {
+	switch typeDesc {
+	case reflect.TypeOf(d):
+		return d, nil
+	case reflect.TypeOf(&big.Rat{}):
+		return d.Rat(), nil
+	case reflect.TypeOf(""):
+		return d.String(), nil
+	case reflect.TypeOf(float64(0)):
+		return d.Float64(), nil
+	}
+	return nil, fmt.Errorf("type conversion error from decimal to %v", typeDesc)
+}
+
+func (d Decimal) ConvertToType(typeVal ref.Type) ref.Val {
+	switch typeVal {
+	case DecimalType:
+		return d
+	case types.StringType:
+		return types.String(d.String())
+	case types.DoubleType:
+		return types.Double(d.Float64())
+	case types.IntType:
+		i := new(big.Int).Quo(d.r.Num(), d.r.Denom())
+		if !i.IsInt64() {
+			return types.NewErr("decimal %s overflows int", d)
+		}
+		return types.Int(i.Int64())
+	case types.TypeType:
+		return DecimalType
+	}
+	return types.NewErr("type conversion error from decimal to %s", typeVal)
+}
+
+func (d Decimal) Equal(other ref.Val) ref.Val {
+	o, ok := toDecimal(other)
+	return types.Bool(ok && d.r.Cmp(o.r) == 0)
+}
+
+func (Decimal) Type() ref.Type {
+	return DecimalType
+}
+
+// Value returns the decimal as a *big.Rat.
+func (d Decimal) Value() any {
+	return d.Rat()
+}
+
+// toDecimal converts decimals and integers.
+func toDecimal(val ref.Val) (Decimal, bool) {
+	switch v := val.(type) {
+	case Decimal:
+		return v, true
+	case types.Int:
+		return NewDecimalFromInt(int64(v)), true
+	case types.Uint:
+		return Decimal{new(big.Rat).SetUint64(uint64(v))}, true
+	}
+	return Decimal{}, false
+}
+
+// operand converts the right operand of an operation on a decimal.
+func operand(other ref.Val) (Decimal, ref.Val) {
+	o, ok := toDecimal(other)
+	if !ok {
+		return Decimal{}, types.MaybeNoSuchOverloadErr(other)
+	}
+	return o, nil
+}
+
+func (d Decimal) Add(other ref.Val) ref.Val {
+	o, err := operand(other)
+	if err != nil {
+		return err
+	}
+	return Decimal{new(big.Rat).Add(d.r, o.r)}
+}
+
+func (d Decimal) Subtract(other ref.Val) ref.Val {
+	o, err := operand(other)
+	if err != nil {
+		return err
+	}
+	return Decimal{new(big.Rat).Sub(d.r, o.r)}
+}
+
+func (d Decimal) Multiply(other ref.Val) ref.Val {
+	o, err := operand(other)
+	if err != nil {
+		return err
+	}
+	return Decimal{new(big.Rat).Mul(d.r, o.r)}
+}
+
+func (d Decimal) Divide(other ref.Val) ref.Val {
+	o, err := operand(other)
+	if err != nil {
+		return err
+	}
+	if o.r.Sign() == 0 {
+		return types.NewErr("division by zero")
+	}
+	return Decimal{new(big.Rat).Quo(d.r, o.r)}
+}
+
+func (d Decimal) Negate() ref.Val {
+	return Decimal{new(big.Rat).Neg(d.r)}
+}
+
+func (d Decimal) Compare(other ref.Val) ref.Val {
+	o, err := operand(other)
+	if err != nil {
+		return err
+	}
+	return types.Int(d.r.Cmp(o.r))
+}
+
+// roundBinding checks the number of digits a decimal is rounded to.
+func roundBinding(round func(d Decimal, places int64) Decimal) func(d, places ref.Val) ref.Val {
+	return func(d, places ref.Val) ref.Val {
+		p := int64(places.(types.Int))
+		if p < -maxDecimalExponent || p > maxDecimalExponent {
+			return types.NewErr("cannot round to %d digits", p)
+		}
+		return round(d.(Decimal), p)
+	}
+}
+
+func decimalOptions() []cel.EnvOption {
+	opts := []cel.EnvOption{
+		cel.Types(DecimalType),
+
+		cel.Function("decimal",
+			cel.Overload("decimal_string", []*cel.Type{cel.StringType}, DecimalType,
+				cel.UnaryBinding(func(s ref.Val) ref.Val {
+					d, err := ParseDecimal(string(s.(types.String)))
+					if err != nil {
+						return types.WrapErr(err)
+					}
+					return d
+				})),
+			cel.Overload("decimal_int", []*cel.Type{cel.IntType}, DecimalType,
+				cel.UnaryBinding(func(i ref.Val) ref.Val {
+					d, _ := toDecimal(i)
+					return d
+				})),
+			cel.Overload("decimal_uint", []*cel.Type{cel.UintType}, DecimalType,
+				cel.UnaryBinding(func(u ref.Val) ref.Val {
+					d, _ := toDecimal(u)
+					return d
+				})),
+			cel.Overload("decimal_double", []*cel.Type{cel.DoubleType}, DecimalType,
+				cel.UnaryBinding(func(f ref.Val) ref.Val {
+					d, err := NewDecimalFromFloat(float64(f.(types.Double)))
+					if err != nil {
+						return types.WrapErr(err)
+					}
+					return d
+				})),
+			cel.Overload("decimal_decimal", []*cel.Type{DecimalType}, DecimalType,
+				cel.UnaryBinding(func(d ref.Val) ref.Val {
+					return d
+				}))),
+		cel.Function(overloads.TypeConvertString,
+			cel.Overload("string_decimal", []*cel.Type{DecimalType}, cel.StringType,
+				cel.UnaryBinding(func(d ref.Val) ref.Val {
+					return d.ConvertToType(types.StringType)
+				}))),
+		cel.Function(overloads.TypeConvertDouble,
+			cel.Overload("double_decimal", []*cel.Type{DecimalType}, cel.DoubleType,
+				cel.UnaryBinding(func(d ref.Val) ref.Val {
+					return d.ConvertToType(types.DoubleType)
+				}))),
+		cel.Function(overloads.TypeConvertInt,
+			cel.Overload("int_decimal", []*cel.Type{DecimalType}, cel.IntType,
+				cel.UnaryBinding(func(d ref.Val) ref.Val {
+					return d.ConvertToType(types.IntType)
+				}))),
+
+		cel.Function("round",
+			cel.MemberOverload("decimal_round_int", []*cel.Type{DecimalType, cel.IntType}, DecimalType,
+				cel.BinaryBinding(roundBinding(func(d Decimal, places int64) Decimal {
+					return d.Round(places, false)
+				})))),
+		cel.Function("roundHalfEven",
+			cel.MemberOverload("decimal_round_half_even_int", []*cel.Type{DecimalType, cel.IntType}, DecimalType,
+				cel.BinaryBinding(roundBinding(func(d Decimal, places int64) Decimal {
+					return d.Round(places, true)
+				})))),
+		cel.Function("truncate",
+			cel.MemberOverload("decimal_truncate_int", []*cel.Type{DecimalType, cel.IntType}, DecimalType,
+				cel.BinaryBinding(roundBinding(Decimal.Truncate)))),
+		cel.Function("sum",
+			cel.MemberOverload("list_sum_decimal", []*cel.Type{cel.ListType(DecimalType)}, DecimalType,
+				cel.UnaryBinding(listSum(NewDecimalFromInt(0))))),
+	}
+
+	// The standard operators dispatch to the traits of their left operand,
+	// so the overloads only declare the decimal operands
+	opts = append(opts, cel.Function(operators.Negate,
+		cel.Overload("negate_decimal", []*cel.Type{DecimalType}, DecimalType)))
+	binary := []struct {
+		operator string
+		name     string
+		result   *cel.Type
+	}{
+		{operators.Add, "add", DecimalType},
+		{operators.Subtract, "subtract", DecimalType},
+		{operators.Multiply, "multiply", DecimalType},
+		{operators.Divide, "divide", DecimalType},
+		{operators.Less, "less", cel.BoolType},
+		{operators.LessEquals, "less_equals", cel.BoolType},
+		{operators.Greater, "greater", cel.BoolType},
+		{operators.GreaterEquals, "greater_equals", cel.BoolType},
+	}
+	for _, op := range binary {
+		fnOpts := []cel.FunctionOpt{
+			cel.Overload(op.name+"_decimal_decimal", []*cel.Type{DecimalType, DecimalType}, op.result),
+		}
+		// An arithmetic overload on an integer would make the result of the
+		// operations on dynamic values and integers dynamic too
+		if op.result == cel.BoolType {
+			fnOpts = append(fnOpts, cel.Overload(op.name+"_decimal_int", []*cel.Type{DecimalType, cel.IntType}, op.result))
+		}
+		opts = append(opts, cel.Function(op.operator, fnOpts...))
+	}
+	return opts
+}
+
+// decimalDecorator turns decimal() of a literal into a constant when the
+// program is planned, so that invalid literals fail the compilation.
+func decimalDecorator(i interpreter.Interpretable) (interpreter.Interpretable, error) {
+	call, ok := i.(interpreter.InterpretableCall)
+	if !ok || call.Function() != "decimal" || len(call.Args()) != 1 {
+		return i, nil
+	}
+	if _, isConst := call.Args()[0].(interpreter.InterpretableConst); !isConst {
+		return i, nil
+	}
+	val := call.Eval(interpreter.EmptyActivation())
+	if types.IsError(val) {
+		return nil, fmt.Errorf("decimal(): %v", val)
+	}
+	return interpreter.NewConstValue(call.ID(), val), nil
+}
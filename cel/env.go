@@ -5,48 +5,171 @@ import (
 	"github.com/google/cel-go/checker/decls"
 	"github.com/google/cel-go/common/types"
 	"github.com/google/cel-go/common/types/ref"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
+// ResultType is the type returned by the Result function of the rule
+// environment.
+var ResultType = cel.MapType(cel.StringType, cel.AnyType)
+
+// resultMetadataType is the type of the metadata argument of Result.
+var resultMetadataType = cel.MapType(cel.StringType, cel.DynType)
+
 func CreatePolicyEnv() (*cel.Env, error) {
-	return cel.NewEnv(
-		cel.Declarations(
-			decls.NewVar("input", decls.NewMapType(decls.String, decls.Any)),
-		),
-	)
+	return NewPolicyEnv(nil)
 }
 
 func CreateRuleEnv() (*cel.Env, error) {
-	return cel.NewEnv(
-		cel.Declarations(
-			decls.NewVar("input", decls.NewMapType(decls.String, decls.Any)),
-		),
+	return NewRuleEnv(nil)
+}
+
+// NewPolicyEnv creates the environment of the policy expressions, where input
+// is a message of the given type. If the type is nil, input is a map of
+// dynamic values. The functions of the latest version of the Library are
+// available, together with the ones added by opts; if opts include the
+// Library of another version, that version is available instead.
+func NewPolicyEnv(input protoreflect.MessageDescriptor, opts ...cel.EnvOption) (*cel.Env, error) {
+	return cel.NewEnv(envOptions(input, opts)...)
+}
+
+// NewRuleEnv creates the environment of the rule expressions, where input is
+// declared as in NewPolicyEnv.
+func NewRuleEnv(input protoreflect.MessageDescriptor, opts ...cel.EnvOption) (*cel.Env, error) {
+	return cel.NewEnv(append(envOptions(input, opts),
+		// Result(value, stop) may also carry a reason code, a message and
+		// metadata, reported with the result of the rule
 		cel.Function("Result",
 			cel.Overload("Result_create",
 				[]*cel.Type{cel.AnyType, cel.BoolType},
-				cel.MapType(cel.StringType, cel.AnyType),
-				cel.FunctionBinding(func(args ...ref.Val) ref.Val {
-					if len(args) != 2 {
-						return types.NewErr("Result requires exactly two arguments")
-					}
-					var value interface{}
-					switch v := args[0].(type) {
-					case types.Int:
-						value = int64(v)
-					case types.Double:
-						value = float64(v)
-					default:
-						return types.NewErr("The first argument must be an integer or a float")
-					}
-					boolVal, ok := args[1].(types.Bool)
-					if !ok {
-						return types.NewErr("The second argument must be a boolean")
-					}
-					return types.NewStringInterfaceMap(types.DefaultTypeAdapter, map[string]any{
-						"value": value,
-						"stop":  bool(boolVal),
-					})
-				}),
+				ResultType,
+				cel.FunctionBinding(newResult),
+			),
+			cel.Overload("Result_create_reason",
+				[]*cel.Type{cel.AnyType, cel.BoolType, cel.StringType},
+				ResultType,
+				cel.FunctionBinding(newResult),
+			),
+			cel.Overload("Result_create_reason_message",
+				[]*cel.Type{cel.AnyType, cel.BoolType, cel.StringType, cel.StringType},
+				ResultType,
+				cel.FunctionBinding(newResult),
+			),
+			cel.Overload("Result_create_reason_message_metadata",
+				[]*cel.Type{cel.AnyType, cel.BoolType, cel.StringType, cel.StringType, resultMetadataType},
+				ResultType,
+				cel.FunctionBinding(newResult),
 			),
 		),
+	)...)
+}
+
+// newResult builds the map returned by Result: value and stop, and the
+// reason, message and metadata if they are given.
+func newResult(args ...ref.Val) ref.Val {
+	if len(args) < 2 || len(args) > 5 {
+		return types.NewErr("Result requires from two to five arguments")
+	}
+	var value interface{}
+	switch v := args[0].(type) {
+	case types.Int:
+		value = int64(v)
+	case types.Double:
+		value = float64(v)
+	case Decimal:
+		value = v.Rat()
+	default:
+		return types.NewErr("The first argument must be an integer, a float or a decimal")
+	}
+	boolVal, ok := args[1].(types.Bool)
+	if !ok {
+		return types.NewErr("The second argument must be a boolean")
+	}
+
+	result := map[string]any{
+		"value": value,
+		"stop":  bool(boolVal),
+	}
+	for i, key := range []string{"reason", "message"} {
+		if len(args) <= i+2 {
+			break
+		}
+		text, ok := args[i+2].(types.String)
+		if !ok {
+			return types.NewErr("The %s must be a string", key)
+		}
+		result[key] = string(text)
+	}
+	if len(args) == 5 {
+		// The metadata is kept as a CEL value, and converted when the result
+		// is reported
+		result["metadata"] = args[4]
+	}
+	return types.NewStringInterfaceMap(types.DefaultTypeAdapter, result)
+}
+
+// AggregationResultsType is the type of the results variable of the
+// aggregation environment.
+var AggregationResultsType = cel.ListType(cel.MapType(cel.StringType, cel.DynType))
+
+// NewAggregationEnv extends a policy environment with results, the list of
+// the results of the executed rules that the custom aggregation expressions
+// combine into the score of the policy.
+func NewAggregationEnv(policyEnv *cel.Env) (*cel.Env, error) {
+	return policyEnv.Extend(cel.Variable("results", AggregationResultsType))
+}
+
+// DecisionRulesType is the type of the rules variable of the decision
+// environment.
+var DecisionRulesType = cel.MapType(cel.StringType, cel.MapType(cel.StringType, cel.DynType))
+
+// NewDecisionEnv extends a policy environment with score, the score of the
+// policy, and rules, the results of its rules by name. The expressions that
+// run after the scoring are evaluated in it: the threshold conditions, the
+// publish actions and the outputs.
+func NewDecisionEnv(policyEnv *cel.Env) (*cel.Env, error) {
+	return policyEnv.Extend(
+		cel.Variable("score", cel.DoubleType),
+		cel.Variable("rules", DecisionRulesType),
 	)
 }
+
+func envOptions(input protoreflect.MessageDescriptor, opts []cel.EnvOption) []cel.EnvOption {
+	// The library is loaded only once, so the latest version is ignored if
+	// opts already include a version of it. The macro calls are tracked to
+	// print the comprehensions when the evaluation is explained.
+	options := append(inputDeclaration(input), opts...)
+	return append(options, Library(LibraryVersion), cel.EnableMacroCallTracking())
+}
+
+func inputDeclaration(input protoreflect.MessageDescriptor) []cel.EnvOption {
+	if input == nil {
+		return []cel.EnvOption{
+			cel.Declarations(
+				decls.NewVar("input", decls.NewMapType(decls.String, decls.Any)),
+			),
+		}
+	}
+	return []cel.EnvOption{
+		cel.TypeDescs(fileDescriptors(input.ParentFile(), nil)...),
+		cel.Variable("input", cel.ObjectType(string(input.FullName()))),
+	}
+}
+
+// fileDescriptors returns file and all the files it imports, which must be
+// registered in the environment together with it.
+func fileDescriptors(file protoreflect.FileDescriptor, seen map[string]bool) []any {
+	if seen == nil {
+		seen = make(map[string]bool)
+	}
+	if seen[file.Path()] {
+		return nil
+	}
+	seen[file.Path()] = true
+
+	files := []any{file}
+	imports := file.Imports()
+	for i := 0; i < imports.Len(); i++ {
+		files = append(files, fileDescriptors(imports.Get(i).FileDescriptor, seen)...)
+	}
+	return files
+}
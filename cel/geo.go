@@ -0,0 +1,296 @@
+package cel
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/interpreter"
+)
+
+// GeofenceType is the CEL type of the geofences returned by geofence().
+var GeofenceType = cel.OpaqueType("geo.Geofence")
+
+// earthRadius is the mean radius of the Earth in meters.
+const earthRadius = 6371008.8
+
+// Distance returns the haversine distance in meters between two points given
+// as latitude and longitude in degrees.
+func Distance(lat1, lon1, lat2, lon2 float64) float64 {
+	phi1, phi2 := lat1*math.Pi/180, lat2*math.Pi/180
+	dPhi := (lat2 - lat1) * math.Pi / 180
+	dLambda := (lon2 - lon1) * math.Pi / 180
+
+	a := math.Sin(dPhi/2)*math.Sin(dPhi/2) +
+		math.Cos(phi1)*math.Cos(phi2)*math.Sin(dLambda/2)*math.Sin(dLambda/2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// box is a bounding box in degrees. A box crossing the antimeridian has west
+// greater than east.
+type box struct {
+	south, west, north, east float64
+}
+
+func (b box) contains(lat, lon float64) bool {
+	if lat < b.south || lat > b.north {
+		return false
+	}
+	if b.west <= b.east {
+		return lon >= b.west && lon <= b.east
+	}
+	return lon >= b.west || lon <= b.east
+}
+
+// polygon is a GeoJSON polygon: an exterior ring followed by the holes. Each
+// point is a longitude and latitude pair.
+type polygon struct {
+	rings  [][][2]float64
+	bounds box
+}
+
+func (p *polygon) contains(lat, lon float64) bool {
+	if !p.bounds.contains(lat, lon) || !ringContains(p.rings[0], lat, lon) {
+		return false
+	}
+	for _, hole := range p.rings[1:] {
+		if ringContains(hole, lat, lon) {
+			return false
+		}
+	}
+	return true
+}
+
+// ringContains is the even-odd rule on a closed ring, with coordinates
+// handled as planar.
+func ringContains(ring [][2]float64, lat, lon float64) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		xi, yi := ring[i][0], ring[i][1]
+		xj, yj := ring[j][0], ring[j][1]
+		if (yi > lat) != (yj > lat) && lon < (xj-xi)*(lat-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// Geofence is an area made of one or more polygons. The bounding box of each
+// polygon is computed when the geofence is parsed, so most of the points
+// outside the area are rejected without testing the polygon edges.
+type Geofence struct {
+	polygons []*polygon
+}
+
+// ParseGeofence parses a GeoJSON Polygon or MultiPolygon geometry, or a
+// Feature or FeatureCollection of them.
+func ParseGeofence(geojson string) (*Geofence, error) {
+	g := &Geofence{}
+	if err := g.add([]byte(geojson)); err != nil {
+		return nil, err
+	}
+	if len(g.polygons) == 0 {
+		return nil, fmt.Errorf("geofence has no polygons")
+	}
+	return g, nil
+}
+
+func (g *Geofence) add(data json.RawMessage) error {
+	var obj struct {
+		Type        string            `json:"type"`
+		Coordinates json.RawMessage   `json:"coordinates"`
+		Geometry    json.RawMessage   `json:"geometry"`
+		Features    []json.RawMessage `json:"features"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return fmt.Errorf("invalid GeoJSON: %v", err)
+	}
+
+	switch obj.Type {
+	case "Polygon":
+		var rings [][][2]float64
+		if err := json.Unmarshal(obj.Coordinates, &rings); err != nil {
+			return fmt.Errorf("invalid Polygon coordinates: %v", err)
+		}
+		return g.addPolygon(rings)
+	case "MultiPolygon":
+		var polygons [][][][2]float64
+		if err := json.Unmarshal(obj.Coordinates, &polygons); err != nil {
+			return fmt.Errorf("invalid MultiPolygon coordinates: %v", err)
+		}
+		for _, rings := range polygons {
+			if err := g.addPolygon(rings); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "Feature":
+		return g.add(obj.Geometry)
+	case "FeatureCollection":
+		for _, feature := range obj.Features {
+			if err := g.add(feature); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported GeoJSON type %q", obj.Type)
+	}
+}
+
+func (g *Geofence) addPolygon(rings [][][2]float64) error {
+	if len(rings) == 0 {
+		return fmt.Errorf("polygon has no rings")
+	}
+	for _, ring := range rings {
+		if len(ring) < 4 || ring[0] != ring[len(ring)-1] {
+			return fmt.Errorf("polygon rings must be closed and have at least 4 positions")
+		}
+		for _, p := range ring {
+			if p[0] < -180 || p[0] > 180 || p[1] < -90 || p[1] > 90 {
+				return fmt.Errorf("position [%v, %v] is out of range", p[0], p[1])
+			}
+		}
+	}
+
+	bounds := box{south: 90, west: 180, north: -90, east: -180}
+	for _, p := range rings[0] {
+		bounds.west = math.Min(bounds.west, p[0])
+		bounds.east = math.Max(bounds.east, p[0])
+		bounds.south = math.Min(bounds.south, p[1])
+		bounds.north = math.Max(bounds.north, p[1])
+	}
+	g.polygons = append(g.polygons, &polygon{rings: rings, bounds: bounds})
+	return nil
+}
+
+// Contains reports whether a point is inside one of the polygons of the
+// geofence.
+func (g *Geofence) Contains(lat, lon float64) bool {
+	for _, p := range g.polygons {
+		if p.contains(lat, lon) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Geofence) ConvertToNative(typeDesc reflect.Type) (any, error) {
+	if typeDesc == reflect.TypeOf(g) {
+		return g, nil
+	}
+	return nil, fmt.Errorf("type conversion error from geo.Geofence to %v", typeDesc)
+}
+
+func (g *Geofence) ConvertToType(typeVal ref.Type) ref.Val {
+	switch typeVal {
+	case GeofenceType:
+		return g
+	case types.TypeType:
+		return GeofenceType
+	}
+	return types.NewErr("type conversion error from geo.Geofence to %s", typeVal)
+}
+
+func (g *Geofence) Equal(other ref.Val) ref.Val {
+	return types.Bool(g == other)
+}
+
+func (*Geofence) Type() ref.Type {
+	return GeofenceType
+}
+
+func (g *Geofence) Value() any {
+	return g
+}
+
+func geoOptions() []cel.EnvOption {
+	return []cel.EnvOption{
+		cel.Function("geo.distance",
+			cel.Overload("geo_distance_double_double_double_double",
+				[]*cel.Type{cel.DoubleType, cel.DoubleType, cel.DoubleType, cel.DoubleType}, cel.DoubleType,
+				cel.FunctionBinding(func(args ...ref.Val) ref.Val {
+					return types.Double(Distance(
+						float64(args[0].(types.Double)), float64(args[1].(types.Double)),
+						float64(args[2].(types.Double)), float64(args[3].(types.Double)),
+					))
+				}))),
+		cel.Function("geo.inBox",
+			cel.Overload("geo_in_box_double_double_double_double_double_double",
+				[]*cel.Type{cel.DoubleType, cel.DoubleType, cel.DoubleType, cel.DoubleType, cel.DoubleType, cel.DoubleType}, cel.BoolType,
+				cel.FunctionBinding(func(args ...ref.Val) ref.Val {
+					b := box{
+						south: float64(args[2].(types.Double)),
+						west:  float64(args[3].(types.Double)),
+						north: float64(args[4].(types.Double)),
+						east:  float64(args[5].(types.Double)),
+					}
+					return types.Bool(b.contains(float64(args[0].(types.Double)), float64(args[1].(types.Double))))
+				}))),
+	}
+}
+
+// Geofences returns the option that adds the geofence function to an
+// environment:
+//
+//	geofence(<string>) -> <geo.Geofence>
+//	<geo.Geofence>.contains(<double>, <double>) -> <bool>
+//
+// geofence returns the geofence with the given name, and contains tests a
+// latitude and longitude against it. A constant name is resolved when the
+// program is created, and an unknown one fails the compilation.
+func Geofences(geofences map[string]*Geofence) cel.EnvOption {
+	return cel.Lib(&geofenceLib{geofences: geofences})
+}
+
+type geofenceLib struct {
+	geofences map[string]*Geofence
+}
+
+func (*geofenceLib) LibraryName() string {
+	return "sandrolain.rules.geofences"
+}
+
+func (lib *geofenceLib) CompileOptions() []cel.EnvOption {
+	return []cel.EnvOption{
+		cel.Types(GeofenceType),
+		cel.Function("geofence",
+			cel.Overload("geofence_string", []*cel.Type{cel.StringType}, GeofenceType,
+				cel.UnaryBinding(func(name ref.Val) ref.Val {
+					g, ok := lib.geofences[string(name.(types.String))]
+					if !ok {
+						return types.NewErr("unknown geofence %q", name)
+					}
+					return g
+				}))),
+		cel.Function("contains",
+			cel.MemberOverload("geo_geofence_contains_double_double", []*cel.Type{GeofenceType, cel.DoubleType, cel.DoubleType}, cel.BoolType,
+				cel.FunctionBinding(func(args ...ref.Val) ref.Val {
+					return types.Bool(args[0].(*Geofence).Contains(float64(args[1].(types.Double)), float64(args[2].(types.Double))))
+				}))),
+	}
+}
+
+func (*geofenceLib) ProgramOptions() []cel.ProgramOption {
+	return []cel.ProgramOption{cel.CustomDecorator(geofenceDecorator)}
+}
+
+// geofenceDecorator resolves the geofence() calls with a constant name.
+func geofenceDecorator(i interpreter.Interpretable) (interpreter.Interpretable, error) {
+	call, ok := i.(interpreter.InterpretableCall)
+	if !ok || call.Function() != "geofence" || len(call.Args()) != 1 {
+		return i, nil
+	}
+	if _, isConst := call.Args()[0].(interpreter.InterpretableConst); !isConst {
+		return i, nil
+	}
+	val := call.Eval(interpreter.EmptyActivation())
+	if types.IsError(val) {
+		return nil, fmt.Errorf("geofence(): %v", val)
+	}
+	return interpreter.NewConstValue(call.ID(), val), nil
+}
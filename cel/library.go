@@ -0,0 +1,437 @@
+package cel
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+	"github.com/google/cel-go/ext"
+)
+
+// LibraryVersion is the latest version of the function library, available in
+// the policy and rule environments unless they are created with the Library
+// of an older version. Functions are only added in new versions, so
+// expressions written for a version keep compiling with the later ones.
+const LibraryVersion = 5
+
+// Library returns the function library of the given version.
+//
+// Version 1 includes version 3 of the cel-go strings extension, version 1 of
+// the math extension and version 0 of the encoders, sets and lists ones, and
+// the following functions:
+//
+//	regex.matches(<string>, <string>) -> <bool>
+//	regex.find(<string>, <string>) -> <string>
+//	regex.findAll(<string>, <string>) -> <list<string>>
+//	regex.replace(<string>, <string>, <string>) -> <string>
+//
+// The regex functions return the first match, or an empty string, all the
+// matches and the target with the matches replaced. Their patterns are
+// compiled once and cached, even when they are built at evaluation time.
+//
+//	math.clamp(<int>, <int>, <int>) -> <int>
+//	math.clamp(<double>, <double>, <double>) -> <double>
+//
+// Limits a value to the range given by the second and third arguments.
+//
+//	<list<int>>.sum() -> <int>
+//	<list<uint>>.sum() -> <uint>
+//	<list<double>>.sum() -> <double>
+//	<list<T>>.min() -> <T>
+//	<list<T>>.max() -> <T>
+//	<list<T>>.avg() -> <double>
+//	<list<T>>.distinct() -> <list<T>>
+//
+// Aggregate lists of numbers. The sum of an empty list is the zero of its
+// element type, while min, max and avg of an empty list are errors. distinct
+// keeps the first of the equal elements.
+//
+//	lookup(<dyn>, <string>, <dyn>) -> <dyn>
+//
+// Follows a dotted path of map keys, message fields and list indexes, and
+// returns the value found, or the third argument if any step of the path is
+// missing. For example lookup(input, 'customer.addresses.0.city', 'unknown').
+//
+// Version 2 adds the network functions:
+//
+//	ip(<string>) -> <net.IP>
+//	cidr(<string>) -> <net.CIDR>
+//	isIP(<string>) -> <bool>
+//	string(<net.IP>) -> <string>
+//	string(<net.CIDR>) -> <string>
+//	<net.IP>.family() -> <int>
+//	<net.IP>.isPrivate() -> <bool>
+//	<net.IP>.isLoopback() -> <bool>
+//	<net.IP>.isReserved() -> <bool>
+//	<net.CIDR>.contains(<net.IP>) -> <bool>
+//	<net.IP>.inRanges(<list<string>>) -> <bool>
+//	<net.IP>.inRanges(<list<net.CIDR>>) -> <bool>
+//
+// ip and cidr parse IPv4 and IPv6 addresses and ranges, and are errors for
+// invalid strings. isReserved is true for the special-purpose ranges that are
+// neither private nor loopback, like link local, multicast and documentation
+// addresses. The constant arguments of ip and cidr, and the literal range
+// lists of inRanges, are parsed when the program is created, for example in
+// ip(input.ip).inRanges(['10.0.0.0/8', '192.168.0.0/16']).
+//
+// Version 3 adds the geospatial functions:
+//
+//	geo.distance(<double>, <double>, <double>, <double>) -> <double>
+//	geo.inBox(<double>, <double>, <double>, <double>, <double>, <double>) -> <bool>
+//
+// geo.distance returns the haversine distance in meters between two points,
+// each given as latitude and longitude in degrees. geo.inBox tests whether the
+// point given by the first two arguments is inside the box given by its south,
+// west, north and east bounds; the box crosses the antimeridian if west is
+// greater than east. The geofences declared in a policy are added with
+// Geofences.
+//
+// Version 4 adds the time functions:
+//
+//	time.parse(<string>) -> <timestamp>
+//	time.hour(<timestamp>, <string>) -> <int>
+//	time.weekday(<timestamp>, <string>) -> <int>
+//	time.isWeekend(<timestamp>, <string>) -> <bool>
+//	time.ageDays(<timestamp>) -> <int>
+//	time.ageDays(<timestamp>, <timestamp>) -> <int>
+//	time.ageYears(<timestamp>) -> <int>
+//	time.ageYears(<timestamp>, <timestamp>) -> <int>
+//
+// Every timestamp argument can also be a string, which is parsed in RFC 3339
+// format, as a date and time without offset or as a date, both in UTC. The
+// string argument of hour, weekday and isWeekend is the IANA name of the time
+// zone the time is converted to; weekday is 0 for Sunday. ageDays and
+// ageYears return the whole days and years from the date of the first time to
+// the date of the second one, or to the current date, in UTC. The business
+// day function is added with Calendars.
+//
+// Version 5 adds the exact decimal numbers:
+//
+//	decimal(<string>) -> <decimal>
+//	decimal(<int>) -> <decimal>
+//	decimal(<uint>) -> <decimal>
+//	decimal(<double>) -> <decimal>
+//	string(<decimal>) -> <string>
+//	double(<decimal>) -> <double>
+//	int(<decimal>) -> <int>
+//	<decimal>.round(<int>) -> <decimal>
+//	<decimal>.roundHalfEven(<int>) -> <decimal>
+//	<decimal>.truncate(<int>) -> <decimal>
+//	<list<decimal>>.sum() -> <decimal>
+//
+// Decimals support the arithmetic operators +, -, * and /, the negation and
+// the comparisons, with a decimal as the left operand. The right operand is a
+// decimal, or also an integer for the comparisons, for example
+// decimal('0.1') + decimal('0.2') == decimal('0.3') and
+// input.amount * decimal(3) > 100 for an amount decoded as a decimal. The
+// results are exact, and division by zero is an error. A double is converted
+// through its shortest representation, so decimal(0.1) is 0.1. int truncates
+// toward zero; round rounds the halves away from zero and roundHalfEven to
+// the even digit, to the given number of fractional digits. The constant
+// arguments of decimal are parsed when the program is created.
+func Library(version uint32) cel.EnvOption {
+	return cel.Lib(&rulesLib{version: version})
+}
+
+type rulesLib struct {
+	version uint32
+}
+
+func (*rulesLib) LibraryName() string {
+	return "sandrolain.rules.lib"
+}
+
+func (lib *rulesLib) CompileOptions() []cel.EnvOption {
+	var opts []cel.EnvOption
+	if lib.version >= 1 {
+		opts = append(opts, standardOptions()...)
+	}
+	if lib.version >= 2 {
+		opts = append(opts, networkOptions()...)
+	}
+	if lib.version >= 3 {
+		opts = append(opts, geoOptions()...)
+	}
+	if lib.version >= 4 {
+		opts = append(opts, timeOptions()...)
+	}
+	if lib.version >= 5 {
+		opts = append(opts, decimalOptions()...)
+	}
+	return opts
+}
+
+func (lib *rulesLib) ProgramOptions() []cel.ProgramOption {
+	var opts []cel.ProgramOption
+	if lib.version >= 2 {
+		opts = append(opts, cel.CustomDecorator(networkDecorator))
+	}
+	if lib.version >= 4 {
+		opts = append(opts, cel.CustomDecorator(timeDecorator))
+	}
+	if lib.version >= 5 {
+		opts = append(opts, cel.CustomDecorator(decimalDecorator))
+	}
+	return opts
+}
+
+func standardOptions() []cel.EnvOption {
+	listT := cel.ListType(cel.TypeParamType("T"))
+	return []cel.EnvOption{
+		// The extensions are pinned, so that upgrading cel-go does not change
+		// the functions of a version
+		ext.Strings(ext.StringsVersion(3)),
+		ext.Math(ext.MathVersion(1)),
+		ext.Encoders(ext.EncodersVersion(0)),
+		ext.Sets(ext.SetsVersion(0)),
+		ext.Lists(ext.ListsVersion(0)),
+
+		cel.Function("regex.matches",
+			cel.Overload("regex_matches_string_string", []*cel.Type{cel.StringType, cel.StringType}, cel.BoolType,
+				cel.BinaryBinding(regexMatches))),
+		cel.Function("regex.find",
+			cel.Overload("regex_find_string_string", []*cel.Type{cel.StringType, cel.StringType}, cel.StringType,
+				cel.BinaryBinding(regexFind))),
+		cel.Function("regex.findAll",
+			cel.Overload("regex_find_all_string_string", []*cel.Type{cel.StringType, cel.StringType}, cel.ListType(cel.StringType),
+				cel.BinaryBinding(regexFindAll))),
+		cel.Function("regex.replace",
+			cel.Overload("regex_replace_string_string_string", []*cel.Type{cel.StringType, cel.StringType, cel.StringType}, cel.StringType,
+				cel.FunctionBinding(regexReplace))),
+
+		cel.Function("math.clamp",
+			cel.Overload("math_clamp_int", []*cel.Type{cel.IntType, cel.IntType, cel.IntType}, cel.IntType,
+				cel.FunctionBinding(clamp)),
+			cel.Overload("math_clamp_double", []*cel.Type{cel.DoubleType, cel.DoubleType, cel.DoubleType}, cel.DoubleType,
+				cel.FunctionBinding(clamp))),
+
+		cel.Function("sum",
+			cel.MemberOverload("list_sum_int", []*cel.Type{cel.ListType(cel.IntType)}, cel.IntType,
+				cel.UnaryBinding(listSum(types.IntZero))),
+			cel.MemberOverload("list_sum_uint", []*cel.Type{cel.ListType(cel.UintType)}, cel.UintType,
+				cel.UnaryBinding(listSum(types.Uint(0)))),
+			cel.MemberOverload("list_sum_double", []*cel.Type{cel.ListType(cel.DoubleType)}, cel.DoubleType,
+				cel.UnaryBinding(listSum(types.Double(0))))),
+		cel.Function("min",
+			cel.MemberOverload("list_min", []*cel.Type{listT}, cel.TypeParamType("T"),
+				cel.UnaryBinding(func(list ref.Val) ref.Val { return listExtreme(list, types.IntNegOne) }))),
+		cel.Function("max",
+			cel.MemberOverload("list_max", []*cel.Type{listT}, cel.TypeParamType("T"),
+				cel.UnaryBinding(func(list ref.Val) ref.Val { return listExtreme(list, types.IntOne) }))),
+		cel.Function("avg",
+			cel.MemberOverload("list_avg", []*cel.Type{listT}, cel.DoubleType,
+				cel.UnaryBinding(listAvg))),
+		cel.Function("distinct",
+			cel.MemberOverload("list_distinct", []*cel.Type{listT}, listT,
+				cel.UnaryBinding(listDistinct))),
+
+		cel.Function("lookup",
+			cel.Overload("lookup_dyn_string_dyn", []*cel.Type{cel.DynType, cel.StringType, cel.DynType}, cel.DynType,
+				cel.FunctionBinding(lookup))),
+	}
+}
+
+// maxCachedPatterns bounds the regex cache. When it is full the cache is
+// cleared, which is enough for the few distinct patterns rules normally use.
+const maxCachedPatterns = 1024
+
+var patternCache = struct {
+	sync.Mutex
+	patterns map[string]*regexp.Regexp
+}{patterns: make(map[string]*regexp.Regexp)}
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	patternCache.Lock()
+	defer patternCache.Unlock()
+
+	if re, ok := patternCache.patterns[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(patternCache.patterns) >= maxCachedPatterns {
+		patternCache.patterns = make(map[string]*regexp.Regexp)
+	}
+	patternCache.patterns[pattern] = re
+	return re, nil
+}
+
+func regexMatches(target, pattern ref.Val) ref.Val {
+	re, err := compilePattern(string(pattern.(types.String)))
+	if err != nil {
+		return types.WrapErr(err)
+	}
+	return types.Bool(re.MatchString(string(target.(types.String))))
+}
+
+func regexFind(target, pattern ref.Val) ref.Val {
+	re, err := compilePattern(string(pattern.(types.String)))
+	if err != nil {
+		return types.WrapErr(err)
+	}
+	return types.String(re.FindString(string(target.(types.String))))
+}
+
+func regexFindAll(target, pattern ref.Val) ref.Val {
+	re, err := compilePattern(string(pattern.(types.String)))
+	if err != nil {
+		return types.WrapErr(err)
+	}
+	matches := re.FindAllString(string(target.(types.String)), -1)
+	return types.DefaultTypeAdapter.NativeToValue(append([]string{}, matches...))
+}
+
+func regexReplace(args ...ref.Val) ref.Val {
+	re, err := compilePattern(string(args[1].(types.String)))
+	if err != nil {
+		return types.WrapErr(err)
+	}
+	return types.String(re.ReplaceAllString(string(args[0].(types.String)), string(args[2].(types.String))))
+}
+
+func clamp(args ...ref.Val) ref.Val {
+	value, low, high := args[0].(traits.Comparer), args[1], args[2]
+	if args[1].(traits.Comparer).Compare(high) == types.IntOne {
+		return types.NewErr("math.clamp: the lower bound is greater than the upper bound")
+	}
+	if value.Compare(low) == types.IntNegOne {
+		return low
+	}
+	if value.Compare(high) == types.IntOne {
+		return high
+	}
+	return args[0]
+}
+
+// listSum returns the binding of a sum overload, where the sum of an empty
+// list is the zero of the element type of the overload.
+func listSum(zero ref.Val) func(ref.Val) ref.Val {
+	return func(list ref.Val) ref.Val {
+		sum := zero
+		it := list.(traits.Lister).Iterator()
+		for i := 0; it.HasNext() == types.True; i++ {
+			elem := it.Next()
+			// Lists typed dyn may hold any numbers, so the sum starts from
+			// the first element rather than from the zero
+			if i == 0 {
+				sum = elem
+				continue
+			}
+			adder, ok := sum.(traits.Adder)
+			if !ok {
+				return types.NewErr("sum: unsupported element type %s", sum.Type().TypeName())
+			}
+			sum = adder.Add(elem)
+			if types.IsError(sum) {
+				return sum
+			}
+		}
+		return sum
+	}
+}
+
+// listExtreme returns the element of the list that compares to all the others
+// with the given result: the greatest one for 1, the least one for -1.
+func listExtreme(list ref.Val, want types.Int) ref.Val {
+	it := list.(traits.Lister).Iterator()
+	if it.HasNext() != types.True {
+		return types.NewErr("empty list")
+	}
+	best := it.Next()
+	for it.HasNext() == types.True {
+		elem := it.Next()
+		cmp, ok := elem.(traits.Comparer)
+		if !ok {
+			return types.NewErr("unsupported element type %s", elem.Type().TypeName())
+		}
+		res := cmp.Compare(best)
+		if types.IsError(res) {
+			return res
+		}
+		if res == want {
+			best = elem
+		}
+	}
+	return best
+}
+
+func listAvg(list ref.Val) ref.Val {
+	lister := list.(traits.Lister)
+	size := int64(lister.Size().(types.Int))
+	if size == 0 {
+		return types.NewErr("avg: empty list")
+	}
+
+	var sum float64
+	it := lister.Iterator()
+	for it.HasNext() == types.True {
+		d := it.Next().ConvertToType(types.DoubleType)
+		if types.IsError(d) {
+			return d
+		}
+		sum += float64(d.(types.Double))
+	}
+	return types.Double(sum / float64(size))
+}
+
+func listDistinct(list ref.Val) ref.Val {
+	var distinct []ref.Val
+	it := list.(traits.Lister).Iterator()
+	for it.HasNext() == types.True {
+		elem := it.Next()
+		seen := false
+		for _, d := range distinct {
+			if d.Equal(elem) == types.True {
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			distinct = append(distinct, elem)
+		}
+	}
+	return types.NewRefValList(types.DefaultTypeAdapter, distinct)
+}
+
+func lookup(args ...ref.Val) ref.Val {
+	current, path, def := args[0], string(args[1].(types.String)), args[2]
+	if path == "" {
+		return current
+	}
+
+	for _, step := range strings.Split(path, ".") {
+		switch c := current.(type) {
+		case traits.Mapper:
+			value, found := c.Find(types.String(step))
+			if !found || types.IsError(value) {
+				return def
+			}
+			current = value
+		case traits.Lister:
+			index, err := strconv.Atoi(step)
+			if err != nil || index < 0 || int64(index) >= int64(c.Size().(types.Int)) {
+				return def
+			}
+			current = c.Get(types.Int(index))
+		case traits.FieldTester:
+			// Messages: only the fields that are set are followed
+			if c.IsSet(types.String(step)) != types.True {
+				return def
+			}
+			current = c.(traits.Indexer).Get(types.String(step))
+		default:
+			return def
+		}
+		if current == nil || current == types.NullValue {
+			return def
+		}
+	}
+	return current
+}
@@ -0,0 +1,117 @@
+package cel
+
+import (
+	"testing"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func evaluate(t *testing.T, env *cel.Env, expression string, input map[string]interface{}) (ref.Val, *cel.Type) {
+	t.Helper()
+	ast, iss := env.Compile(expression)
+	require.NoError(t, iss.Err())
+	program, err := env.Program(ast)
+	require.NoError(t, err)
+	out, _, err := program.Eval(map[string]interface{}{"input": input})
+	require.NoError(t, err)
+	return out, ast.OutputType()
+}
+
+func TestLibrary_ListSum(t *testing.T) {
+	env, err := NewPolicyEnv(nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		expression string
+		expected   ref.Val
+		outputType *cel.Type
+	}{
+		{"Ints", "[1, 2, 3].sum()", types.Int(6), cel.IntType},
+		{"Doubles", "[1.5, 2.5].sum()", types.Double(4), cel.DoubleType},
+		{"Decimals", "[decimal('0.1'), decimal('0.2')].sum() == decimal('0.3')", types.True, cel.BoolType},
+		{"Empty ints", "[1, 2].filter(x, x > 2).sum()", types.IntZero, cel.IntType},
+		{"Empty uints", "[1u].filter(x, x > 1u).sum()", types.Uint(0), cel.UintType},
+		{"Empty doubles", "[1.5].filter(x, x > 2.0).sum()", types.Double(0), cel.DoubleType},
+		{"Empty decimals", "[decimal(1)].filter(x, x > 1).sum() == decimal(0)", types.True, cel.BoolType},
+		{"Dyn doubles", "input.amounts.sum()", types.Double(4), cel.DynType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, outputType := evaluate(t, env, tt.expression, map[string]interface{}{"amounts": []float64{1.5, 2.5}})
+			assert.Equal(t, tt.expected, out)
+			assert.True(t, outputType.IsExactType(tt.outputType), outputType.String())
+		})
+	}
+
+	t.Run("Lists of other types", func(t *testing.T) {
+		_, iss := env.Compile("['a', 'b'].sum()")
+		assert.Error(t, iss.Err())
+	})
+}
+
+func TestLibrary_Version(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []cel.EnvOption
+		accepted []string
+		rejected []string
+	}{
+		{
+			name:     "Version 1",
+			opts:     []cel.EnvOption{Library(1)},
+			accepted: []string{"[1, 2].sum() > 2", "math.abs(-1) == 1"},
+			rejected: []string{"decimal('1') > 0", "ip('10.0.0.1').isLoopback()", "geo.inBox(0.0, 0.0, -1.0, -1.0, 1.0, 1.0)", "time.hour('2024-01-01', 'UTC') == 0"},
+		},
+		{
+			name:     "Version 4",
+			opts:     []cel.EnvOption{Library(4)},
+			accepted: []string{"time.hour('2024-01-01', 'UTC') == 0", "ip('10.0.0.1').isLoopback()"},
+			rejected: []string{"decimal('1') > 0"},
+		},
+		{
+			name:     "Latest version",
+			accepted: []string{"decimal('1') > 0", "time.hour('2024-01-01', 'UTC') == 0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, err := NewRuleEnv(nil, tt.opts...)
+			require.NoError(t, err)
+			for _, expression := range tt.accepted {
+				_, iss := env.Compile(expression)
+				assert.NoError(t, iss.Err(), expression)
+			}
+			for _, expression := range tt.rejected {
+				_, iss := env.Compile(expression)
+				assert.Error(t, iss.Err(), expression)
+			}
+		})
+	}
+}
+
+func TestLibrary_PinnedExtensions(t *testing.T) {
+	env, err := NewRuleEnv(nil)
+	require.NoError(t, err)
+
+	// Functions added by later versions of the cel-go extensions are not
+	// part of any version of the library
+	for _, expression := range []string{
+		"[[1], [2]].flatten() == [1, 2]",
+		"[3, 1, 2].sort() == [1, 2, 3]",
+		"lists.range(3) == [0, 1, 2]",
+		"[1, 2].reverse() == [2, 1]",
+	} {
+		_, iss := env.Compile(expression)
+		assert.Error(t, iss.Err(), expression)
+	}
+
+	out, _ := evaluate(t, env, "[1, 2, 1].distinct() == [1, 2] && [1, 2, 3].slice(1, 2) == [2]", nil)
+	assert.Equal(t, types.True, out)
+}
@@ -0,0 +1,368 @@
+package cel
+
+import (
+	"fmt"
+	"net/netip"
+	"reflect"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+	"github.com/google/cel-go/interpreter"
+)
+
+var (
+	// IPType is the CEL type of the IP addresses returned by ip().
+	IPType = cel.OpaqueType("net.IP")
+	// CIDRType is the CEL type of the address ranges returned by cidr().
+	CIDRType = cel.OpaqueType("net.CIDR")
+)
+
+// IP is an IPv4 or IPv6 address. IPv4-mapped IPv6 addresses are stored as
+// IPv4 addresses, so that they match the IPv4 ranges.
+type IP struct {
+	netip.Addr
+}
+
+func (ip IP) ConvertToNative(typeDesc reflect.Type) (any, error) {
+	switch typeDesc {
+	case reflect.TypeOf(netip.Addr{}):
+		return ip.Addr, nil
+	case reflect.TypeOf(""):
+		return ip.String(), nil
+	}
+	return nil, fmt.Errorf("type conversion error from net.IP to %v", typeDesc)
+}
+
+func (ip IP) ConvertToType(typeVal ref.Type) ref.Val {
+	switch typeVal {
+	case IPType:
+		return ip
+	case types.StringType:
+		return types.String(ip.String())
+	case types.TypeType:
+		return IPType
+	}
+	return types.NewErr("type conversion error from net.IP to %s", typeVal)
+}
+
+func (ip IP) Equal(other ref.Val) ref.Val {
+	o, ok := other.(IP)
+	return types.Bool(ok && ip.Addr == o.Addr)
+}
+
+func (IP) Type() ref.Type {
+	return IPType
+}
+
+func (ip IP) Value() any {
+	return ip.Addr
+}
+
+// CIDR is a range of IPv4 or IPv6 addresses.
+type CIDR struct {
+	netip.Prefix
+}
+
+func (c CIDR) ConvertToNative(typeDesc reflect.Type) (any, error) {
+	switch typeDesc {
+	case reflect.TypeOf(netip.Prefix{}):
+		return c.Prefix, nil
+	case reflect.TypeOf(""):
+		return c.String(), nil
+	}
+	return nil, fmt.Errorf("type conversion error from net.CIDR to %v", typeDesc)
+}
+
+func (c CIDR) ConvertToType(typeVal ref.Type) ref.Val {
+	switch typeVal {
+	case CIDRType:
+		return c
+	case types.StringType:
+		return types.String(c.String())
+	case types.TypeType:
+		return CIDRType
+	}
+	return types.NewErr("type conversion error from net.CIDR to %s", typeVal)
+}
+
+func (c CIDR) Equal(other ref.Val) ref.Val {
+	o, ok := other.(CIDR)
+	return types.Bool(ok && c.Prefix == o.Prefix)
+}
+
+func (CIDR) Type() ref.Type {
+	return CIDRType
+}
+
+func (c CIDR) Value() any {
+	return c.Prefix
+}
+
+func (c CIDR) contains(ip IP) bool {
+	return c.Prefix.Contains(ip.Addr)
+}
+
+// ParseIP parses an IPv4 or IPv6 address.
+func ParseIP(s string) (IP, error) {
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return IP{}, err
+	}
+	return IP{addr.Unmap()}, nil
+}
+
+// ParseCIDR parses an address range in CIDR notation, like 10.0.0.0/8. A
+// single address is a range of one address.
+func ParseCIDR(s string) (CIDR, error) {
+	prefix, err := netip.ParsePrefix(s)
+	if err != nil {
+		ip, ipErr := ParseIP(s)
+		if ipErr != nil {
+			return CIDR{}, err
+		}
+		prefix = netip.PrefixFrom(ip.Addr, ip.BitLen())
+	}
+	if prefix.Addr().Is4In6() && prefix.Bits() >= 96 {
+		prefix = netip.PrefixFrom(prefix.Addr().Unmap(), prefix.Bits()-96)
+	}
+	return CIDR{prefix.Masked()}, nil
+}
+
+// reservedRanges are the special-purpose ranges of the IANA registries that
+// are neither private nor loopback.
+var reservedRanges = mustParseCIDRs(
+	"0.0.0.0/8",       // this network
+	"100.64.0.0/10",   // shared address space
+	"169.254.0.0/16",  // link local
+	"192.0.0.0/24",    // IETF protocol assignments
+	"192.0.2.0/24",    // documentation
+	"198.18.0.0/15",   // benchmarking
+	"198.51.100.0/24", // documentation
+	"203.0.113.0/24",  // documentation
+	"224.0.0.0/4",     // multicast
+	"240.0.0.0/4",     // reserved, including the broadcast address
+	"::/128",          // unspecified
+	"64:ff9b:1::/48",  // local-use translation
+	"100::/64",        // discard only
+	"2001::/23",       // IETF protocol assignments
+	"2001:db8::/32",   // documentation
+	"fe80::/10",       // link local
+	"ff00::/8",        // multicast
+)
+
+func mustParseCIDRs(ranges ...string) []CIDR {
+	cidrs := make([]CIDR, len(ranges))
+	for i, r := range ranges {
+		c, err := ParseCIDR(r)
+		if err != nil {
+			panic(err)
+		}
+		cidrs[i] = c
+	}
+	return cidrs
+}
+
+func networkOptions() []cel.EnvOption {
+	return []cel.EnvOption{
+		cel.Types(IPType, CIDRType),
+
+		cel.Function("ip",
+			cel.Overload("ip_string", []*cel.Type{cel.StringType}, IPType,
+				cel.UnaryBinding(func(s ref.Val) ref.Val {
+					ip, err := ParseIP(string(s.(types.String)))
+					if err != nil {
+						return types.WrapErr(err)
+					}
+					return ip
+				}))),
+		cel.Function("cidr",
+			cel.Overload("cidr_string", []*cel.Type{cel.StringType}, CIDRType,
+				cel.UnaryBinding(func(s ref.Val) ref.Val {
+					c, err := ParseCIDR(string(s.(types.String)))
+					if err != nil {
+						return types.WrapErr(err)
+					}
+					return c
+				}))),
+		cel.Function("isIP",
+			cel.Overload("is_ip_string", []*cel.Type{cel.StringType}, cel.BoolType,
+				cel.UnaryBinding(func(s ref.Val) ref.Val {
+					_, err := ParseIP(string(s.(types.String)))
+					return types.Bool(err == nil)
+				}))),
+		cel.Function("string",
+			cel.Overload("string_net_ip", []*cel.Type{IPType}, cel.StringType,
+				cel.UnaryBinding(func(ip ref.Val) ref.Val {
+					return ip.ConvertToType(types.StringType)
+				})),
+			cel.Overload("string_net_cidr", []*cel.Type{CIDRType}, cel.StringType,
+				cel.UnaryBinding(func(c ref.Val) ref.Val {
+					return c.ConvertToType(types.StringType)
+				}))),
+
+		cel.Function("family",
+			cel.MemberOverload("net_ip_family", []*cel.Type{IPType}, cel.IntType,
+				cel.UnaryBinding(func(ip ref.Val) ref.Val {
+					if ip.(IP).Is4() {
+						return types.Int(4)
+					}
+					return types.Int(6)
+				}))),
+		cel.Function("isPrivate",
+			cel.MemberOverload("net_ip_is_private", []*cel.Type{IPType}, cel.BoolType,
+				cel.UnaryBinding(func(ip ref.Val) ref.Val {
+					return types.Bool(ip.(IP).IsPrivate())
+				}))),
+		cel.Function("isLoopback",
+			cel.MemberOverload("net_ip_is_loopback", []*cel.Type{IPType}, cel.BoolType,
+				cel.UnaryBinding(func(ip ref.Val) ref.Val {
+					return types.Bool(ip.(IP).IsLoopback())
+				}))),
+		cel.Function("isReserved",
+			cel.MemberOverload("net_ip_is_reserved", []*cel.Type{IPType}, cel.BoolType,
+				cel.UnaryBinding(func(ip ref.Val) ref.Val {
+					return types.Bool(inRanges(ip.(IP), reservedRanges))
+				}))),
+
+		cel.Function("contains",
+			cel.MemberOverload("net_cidr_contains_net_ip", []*cel.Type{CIDRType, IPType}, cel.BoolType,
+				cel.BinaryBinding(func(c, ip ref.Val) ref.Val {
+					return types.Bool(c.(CIDR).contains(ip.(IP)))
+				}))),
+		cel.Function("inRanges",
+			cel.MemberOverload("net_ip_in_ranges_list_string", []*cel.Type{IPType, cel.ListType(cel.StringType)}, cel.BoolType,
+				cel.BinaryBinding(ipInRanges)),
+			cel.MemberOverload("net_ip_in_ranges_list_net_cidr", []*cel.Type{IPType, cel.ListType(CIDRType)}, cel.BoolType,
+				cel.BinaryBinding(ipInRanges))),
+	}
+}
+
+func ipInRanges(ip, ranges ref.Val) ref.Val {
+	cidrs, err := cidrList(ranges)
+	if err != nil {
+		return types.WrapErr(err)
+	}
+	return types.Bool(inRanges(ip.(IP), cidrs))
+}
+
+func inRanges(ip IP, cidrs []CIDR) bool {
+	for _, c := range cidrs {
+		if c.contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// cidrList converts a list of CIDR strings or values.
+func cidrList(list ref.Val) ([]CIDR, error) {
+	lister, ok := list.(traits.Lister)
+	if !ok {
+		return nil, fmt.Errorf("expected a list of ranges, got %s", list.Type().TypeName())
+	}
+
+	var cidrs []CIDR
+	it := lister.Iterator()
+	for it.HasNext() == types.True {
+		switch elem := it.Next().(type) {
+		case CIDR:
+			cidrs = append(cidrs, elem)
+		case types.String:
+			c, err := ParseCIDR(string(elem))
+			if err != nil {
+				return nil, err
+			}
+			cidrs = append(cidrs, c)
+		default:
+			return nil, fmt.Errorf("invalid range of type %s", elem.Type().TypeName())
+		}
+	}
+	return cidrs, nil
+}
+
+// networkDecorator parses the constant arguments of the network functions
+// when the program is planned: ip() and cidr() of a literal become constants,
+// and the literal range lists of inRanges() are parsed once. Invalid
+// constants fail the compilation.
+func networkDecorator(i interpreter.Interpretable) (interpreter.Interpretable, error) {
+	call, ok := i.(interpreter.InterpretableCall)
+	if !ok {
+		return i, nil
+	}
+	args := call.Args()
+
+	switch call.Function() {
+	case "ip", "cidr":
+		if len(args) != 1 {
+			return i, nil
+		}
+		if _, isConst := args[0].(interpreter.InterpretableConst); !isConst {
+			return i, nil
+		}
+		val := call.Eval(interpreter.EmptyActivation())
+		if types.IsError(val) {
+			return nil, fmt.Errorf("%s(): %v", call.Function(), val)
+		}
+		return interpreter.NewConstValue(call.ID(), val), nil
+
+	case "inRanges":
+		if len(args) != 2 {
+			return i, nil
+		}
+		list, isConst := constValue(args[1])
+		if !isConst {
+			return i, nil
+		}
+		cidrs, err := cidrList(list)
+		if err != nil {
+			return nil, fmt.Errorf("inRanges(): %v", err)
+		}
+		return &evalInRanges{id: call.ID(), ip: args[0], cidrs: cidrs}, nil
+	}
+	return i, nil
+}
+
+// constValue returns the value of a constant or of a list literal of
+// constants.
+func constValue(i interpreter.Interpretable) (ref.Val, bool) {
+	switch inst := i.(type) {
+	case interpreter.InterpretableConst:
+		return inst.Value(), true
+	case interpreter.InterpretableConstructor:
+		if inst.Type() != types.ListType {
+			return nil, false
+		}
+		for _, elem := range inst.InitVals() {
+			if _, isConst := elem.(interpreter.InterpretableConst); !isConst {
+				return nil, false
+			}
+		}
+		return inst.Eval(interpreter.EmptyActivation()), true
+	}
+	return nil, false
+}
+
+// evalInRanges is inRanges() with a pre-parsed list of ranges.
+type evalInRanges struct {
+	id    int64
+	ip    interpreter.Interpretable
+	cidrs []CIDR
+}
+
+func (e *evalInRanges) ID() int64 {
+	return e.id
+}
+
+func (e *evalInRanges) Eval(ctx interpreter.Activation) ref.Val {
+	val := e.ip.Eval(ctx)
+	ip, ok := val.(IP)
+	if !ok {
+		if types.IsUnknownOrError(val) {
+			return val
+		}
+		return types.NoSuchOverloadErr()
+	}
+	return types.Bool(inRanges(ip, e.cidrs))
+}
@@ -0,0 +1,322 @@
+package cel
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const (
+	schemaFile    = "rules/input/schema.proto"
+	schemaPackage = "rules.input"
+	schemaMessage = "Input"
+)
+
+var identifierRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// jsonSchema is the subset of JSON Schema that can be mapped to CEL types.
+type jsonSchema struct {
+	Type                 any                    `json:"type"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	Required             []string               `json:"required"`
+	Items                *jsonSchema            `json:"items"`
+	AdditionalProperties json.RawMessage        `json:"additionalProperties"`
+	Ref                  string                 `json:"$ref"`
+	Format               string                 `json:"format"`
+}
+
+// typeName returns the JSON type of the schema, ignoring "null", or an empty
+// string when the schema allows values of more than one type.
+func (s *jsonSchema) typeName() string {
+	var names []string
+	switch t := s.Type.(type) {
+	case string:
+		names = []string{t}
+	case []any:
+		for _, n := range t {
+			if name, ok := n.(string); ok {
+				names = append(names, name)
+			}
+		}
+	case nil:
+		if s.Properties != nil {
+			return "object"
+		}
+	}
+
+	typeName := ""
+	for _, name := range names {
+		if name == "null" {
+			continue
+		}
+		if typeName != "" {
+			return ""
+		}
+		typeName = name
+	}
+	return typeName
+}
+
+// JSONSchemaMessage builds the message type described by a JSON Schema
+// document, so that it can be used as the type of the input variable.
+//
+// The schema must describe an object. Its properties become fields of type
+// string, int, double, bool, list, map or nested message, following the
+// schema types; strings with the date-time format are timestamps, parsed
+// from their RFC 3339 form when the input is converted. The properties that
+// may have values of any type, or of more than one type, are typed dyn.
+// Required properties must be present in the input. References ($ref) are
+// not supported.
+func JSONSchemaMessage(schema string) (protoreflect.MessageDescriptor, error) {
+	var root jsonSchema
+	if err := json.Unmarshal([]byte(schema), &root); err != nil {
+		return nil, fmt.Errorf("error parsing JSON schema: %v", err)
+	}
+	if root.typeName() != "object" {
+		return nil, fmt.Errorf("the JSON schema must describe an object")
+	}
+
+	b := &schemaBuilder{properties: make(map[*descriptorpb.DescriptorProto]map[string]bool)}
+	msg, err := b.message(schemaMessage, "."+schemaPackage+"."+schemaMessage, &root)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:        proto.String(schemaFile),
+		Package:     proto.String(schemaPackage),
+		Syntax:      proto.String("proto2"),
+		Dependency:  []string{"google/protobuf/struct.proto", "google/protobuf/timestamp.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{msg},
+	}, protoregistry.GlobalFiles)
+	if err != nil {
+		return nil, fmt.Errorf("error building input type: %v", err)
+	}
+	return file.Messages().ByName(schemaMessage), nil
+}
+
+type schemaBuilder struct {
+	// properties holds the property names of each message, which cannot be
+	// used as names of its nested messages
+	properties map[*descriptorpb.DescriptorProto]map[string]bool
+}
+
+// message builds the message of an object schema. fullName is the
+// fully-qualified name of the message, used to refer to its nested messages.
+func (b *schemaBuilder) message(name string, fullName string, s *jsonSchema) (*descriptorpb.DescriptorProto, error) {
+	if s.Ref != "" {
+		return nil, fmt.Errorf("property %s: $ref is not supported", name)
+	}
+
+	msg := &descriptorpb.DescriptorProto{Name: proto.String(name)}
+
+	required := make(map[string]bool, len(s.Required))
+	for _, r := range s.Required {
+		required[r] = true
+	}
+
+	// Fields are numbered in name order, so the same schema always builds
+	// the same message
+	names := make([]string, 0, len(s.Properties))
+	for n := range s.Properties {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	b.properties[msg] = make(map[string]bool, len(names))
+	for _, n := range names {
+		b.properties[msg][n] = true
+	}
+
+	for i, n := range names {
+		if !identifierRegexp.MatchString(n) {
+			return nil, fmt.Errorf("property %s is not a valid CEL identifier", n)
+		}
+		field := &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(n),
+			JsonName: proto.String(n),
+			Number:   proto.Int32(int32(i + 1)),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		}
+		if err := b.field(msg, fullName, field, s.Properties[n]); err != nil {
+			return nil, err
+		}
+		if required[n] && field.GetLabel() != descriptorpb.FieldDescriptorProto_LABEL_REPEATED {
+			field.Label = descriptorpb.FieldDescriptorProto_LABEL_REQUIRED.Enum()
+		}
+		msg.Field = append(msg.Field, field)
+	}
+
+	return msg, nil
+}
+
+// field sets the type of a field of msg from the schema of its property.
+// Nested messages and map entries are added to msg.
+func (b *schemaBuilder) field(msg *descriptorpb.DescriptorProto, fullName string, field *descriptorpb.FieldDescriptorProto, s *jsonSchema) error {
+	if s.Ref != "" {
+		return fmt.Errorf("property %s: $ref is not supported", field.GetName())
+	}
+
+	name := camelCase(field.GetName())
+	switch s.typeName() {
+	case "array":
+		if s.Items == nil || s.Items.typeName() == "array" {
+			return b.element(msg, fullName, field, name, &jsonSchema{Type: "array"})
+		}
+		field.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+		return b.element(msg, fullName, field, name, s.Items)
+	case "object":
+		if s.Properties == nil {
+			if additional := s.additionalSchema(); additional != nil {
+				return b.mapField(msg, fullName, field, additional)
+			}
+		}
+	}
+	return b.element(msg, fullName, field, name, s)
+}
+
+// additionalSchema returns the schema of the additional properties of an
+// object, if it declares a type for them.
+func (s *jsonSchema) additionalSchema() *jsonSchema {
+	if len(s.AdditionalProperties) == 0 {
+		return nil
+	}
+	var additional jsonSchema
+	if err := json.Unmarshal(s.AdditionalProperties, &additional); err != nil {
+		// additionalProperties is a boolean
+		return nil
+	}
+	if additional.typeName() == "" {
+		return nil
+	}
+	return &additional
+}
+
+// element sets the type of a single value of a field. Date-time strings are
+// mapped to google.protobuf.Timestamp, objects to a nested message of msg
+// named after name, objects without properties to google.protobuf.Struct,
+// lists of lists to google.protobuf.ListValue and untyped values to
+// google.protobuf.Value.
+func (b *schemaBuilder) element(msg *descriptorpb.DescriptorProto, fullName string, field *descriptorpb.FieldDescriptorProto, name string, s *jsonSchema) error {
+	switch s.typeName() {
+	case "string":
+		if s.Format == "date-time" {
+			b.messageType(field, ".google.protobuf.Timestamp")
+			return nil
+		}
+		field.Type = descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()
+	case "integer":
+		field.Type = descriptorpb.FieldDescriptorProto_TYPE_INT64.Enum()
+	case "number":
+		field.Type = descriptorpb.FieldDescriptorProto_TYPE_DOUBLE.Enum()
+	case "boolean":
+		field.Type = descriptorpb.FieldDescriptorProto_TYPE_BOOL.Enum()
+	case "array":
+		b.messageType(field, ".google.protobuf.ListValue")
+	case "object":
+		if s.Properties == nil {
+			b.messageType(field, ".google.protobuf.Struct")
+			return nil
+		}
+		name = b.nestedName(msg, name)
+		nested, err := b.message(name, fullName+"."+name, s)
+		if err != nil {
+			return err
+		}
+		msg.NestedType = append(msg.NestedType, nested)
+		b.messageType(field, fullName+"."+name)
+	default:
+		b.messageType(field, ".google.protobuf.Value")
+	}
+	return nil
+}
+
+// mapField makes field a map from strings to the values described by s.
+func (b *schemaBuilder) mapField(msg *descriptorpb.DescriptorProto, fullName string, field *descriptorpb.FieldDescriptorProto, s *jsonSchema) error {
+	name := b.nestedName(msg, camelCase(field.GetName())+"Entry")
+	entry := &descriptorpb.DescriptorProto{
+		Name: proto.String(name),
+		Field: []*descriptorpb.FieldDescriptorProto{{
+			Name:     proto.String("key"),
+			JsonName: proto.String("key"),
+			Number:   proto.Int32(1),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+		}},
+		Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+	}
+	value := &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String("value"),
+		JsonName: proto.String("value"),
+		Number:   proto.Int32(2),
+		Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+	}
+	if s.typeName() == "array" {
+		s = &jsonSchema{Type: "array"}
+	}
+	// Nested messages of the values are declared next to the entry
+	if err := b.element(msg, fullName, value, camelCase(field.GetName())+"Value", s); err != nil {
+		return err
+	}
+	entry.Field = append(entry.Field, value)
+
+	msg.NestedType = append(msg.NestedType, entry)
+	field.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	b.messageType(field, fullName+"."+name)
+	return nil
+}
+
+func (b *schemaBuilder) messageType(field *descriptorpb.FieldDescriptorProto, typeName string) {
+	field.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+	field.TypeName = proto.String(typeName)
+}
+
+// nestedName returns name, or name with a numeric suffix if msg already has
+// a nested message or a field with that name.
+func (b *schemaBuilder) nestedName(msg *descriptorpb.DescriptorProto, name string) string {
+	if name == "" {
+		name = "Object"
+	}
+	taken := func(n string) bool {
+		if b.properties[msg][n] {
+			return true
+		}
+		for _, nested := range msg.NestedType {
+			if nested.GetName() == n {
+				return true
+			}
+		}
+		return false
+	}
+	candidate := name
+	for i := 2; taken(candidate); i++ {
+		candidate = fmt.Sprintf("%s%d", name, i)
+	}
+	return candidate
+}
+
+func camelCase(name string) string {
+	var sb strings.Builder
+	upper := true
+	for _, r := range name {
+		if r == '_' {
+			upper = true
+			continue
+		}
+		if upper {
+			sb.WriteString(strings.ToUpper(string(r)))
+			upper = false
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
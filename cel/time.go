@@ -0,0 +1,374 @@
+package cel
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	// Time zones are resolved also on hosts without a zoneinfo database
+	_ "time/tzdata"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/interpreter"
+)
+
+// timeLayouts are the formats of the strings accepted where a time is
+// expected. The times without an offset are in UTC.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// ParseTime parses a time in RFC 3339 format, or a date and time without
+// offset, or a date, both in UTC.
+func ParseTime(s string) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot parse %q as a time", s)
+}
+
+func toTime(val ref.Val) (time.Time, error) {
+	switch v := val.(type) {
+	case types.Timestamp:
+		return v.Time, nil
+	case types.String:
+		return ParseTime(string(v))
+	}
+	return time.Time{}, fmt.Errorf("expected a timestamp or a string, got %s", val.Type().TypeName())
+}
+
+var locations sync.Map
+
+// loadLocation is time.LoadLocation with a cache, since loading a zone reads
+// and parses its database entry.
+func loadLocation(name string) (*time.Location, error) {
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	locations.Store(name, loc)
+	return loc, nil
+}
+
+// inZone converts a time to the zone named by val.
+func inZone(t time.Time, val ref.Val) (time.Time, error) {
+	loc, err := loadLocation(string(val.(types.String)))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.In(loc), nil
+}
+
+// timeFunction is a function whose first arguments are times, declared with
+// an overload for each combination of timestamp and string arguments.
+type timeFunction struct {
+	name   string
+	times  int
+	params []*cel.Type
+	result *cel.Type
+	impl   func(times []time.Time, args []ref.Val) ref.Val
+}
+
+func (f timeFunction) option() cel.EnvOption {
+	binding := cel.FunctionBinding(func(args ...ref.Val) ref.Val {
+		times := make([]time.Time, f.times)
+		for i := range times {
+			t, err := toTime(args[i])
+			if err != nil {
+				return types.NewErr("%s: %v", f.name, err)
+			}
+			times[i] = t
+		}
+		return f.impl(times, args[f.times:])
+	})
+
+	var overloads []cel.FunctionOpt
+	for combination := 0; combination < 1<<f.times; combination++ {
+		argTypes := make([]*cel.Type, 0, f.times+len(f.params))
+		id := strings.ReplaceAll(f.name, ".", "_")
+		for i := 0; i < f.times; i++ {
+			if combination&(1<<i) == 0 {
+				argTypes = append(argTypes, cel.TimestampType)
+				id += "_timestamp"
+			} else {
+				argTypes = append(argTypes, cel.StringType)
+				id += "_string"
+			}
+		}
+		for _, p := range f.params {
+			argTypes = append(argTypes, p)
+			id += "_" + p.String()
+		}
+		overloads = append(overloads, cel.Overload(id, argTypes, f.result, binding))
+	}
+	return cel.Function(f.name, overloads...)
+}
+
+// ageDays returns the number of whole days from a date to another, taking
+// their dates in UTC.
+func ageDays(from, to time.Time) int64 {
+	y1, m1, d1 := from.UTC().Date()
+	y2, m2, d2 := to.UTC().Date()
+	start := time.Date(y1, m1, d1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(y2, m2, d2, 0, 0, 0, 0, time.UTC)
+	return int64(end.Sub(start).Hours() / 24)
+}
+
+// ageYears returns the number of whole years from a date to another, taking
+// their dates in UTC.
+func ageYears(from, to time.Time) int64 {
+	y1, m1, d1 := from.UTC().Date()
+	y2, m2, d2 := to.UTC().Date()
+	years := y2 - y1
+	if m2 < m1 || (m2 == m1 && d2 < d1) {
+		years--
+	}
+	return int64(years)
+}
+
+func isWeekend(t time.Time) bool {
+	return t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+}
+
+func timeOptions() []cel.EnvOption {
+	functions := []timeFunction{
+		{
+			name: "time.parse", times: 1, result: cel.TimestampType,
+			impl: func(times []time.Time, _ []ref.Val) ref.Val {
+				return types.Timestamp{Time: times[0]}
+			},
+		},
+		{
+			name: "time.hour", times: 1, params: []*cel.Type{cel.StringType}, result: cel.IntType,
+			impl: func(times []time.Time, args []ref.Val) ref.Val {
+				t, err := inZone(times[0], args[0])
+				if err != nil {
+					return types.WrapErr(err)
+				}
+				return types.Int(t.Hour())
+			},
+		},
+		{
+			name: "time.weekday", times: 1, params: []*cel.Type{cel.StringType}, result: cel.IntType,
+			impl: func(times []time.Time, args []ref.Val) ref.Val {
+				t, err := inZone(times[0], args[0])
+				if err != nil {
+					return types.WrapErr(err)
+				}
+				return types.Int(t.Weekday())
+			},
+		},
+		{
+			name: "time.isWeekend", times: 1, params: []*cel.Type{cel.StringType}, result: cel.BoolType,
+			impl: func(times []time.Time, args []ref.Val) ref.Val {
+				t, err := inZone(times[0], args[0])
+				if err != nil {
+					return types.WrapErr(err)
+				}
+				return types.Bool(isWeekend(t))
+			},
+		},
+		{
+			name: "time.ageDays", times: 1, result: cel.IntType,
+			impl: func(times []time.Time, _ []ref.Val) ref.Val {
+				return types.Int(ageDays(times[0], time.Now()))
+			},
+		},
+		{
+			name: "time.ageDays", times: 2, result: cel.IntType,
+			impl: func(times []time.Time, _ []ref.Val) ref.Val {
+				return types.Int(ageDays(times[0], times[1]))
+			},
+		},
+		{
+			name: "time.ageYears", times: 1, result: cel.IntType,
+			impl: func(times []time.Time, _ []ref.Val) ref.Val {
+				return types.Int(ageYears(times[0], time.Now()))
+			},
+		},
+		{
+			name: "time.ageYears", times: 2, result: cel.IntType,
+			impl: func(times []time.Time, _ []ref.Val) ref.Val {
+				return types.Int(ageYears(times[0], times[1]))
+			},
+		},
+	}
+
+	opts := make([]cel.EnvOption, len(functions))
+	for i, f := range functions {
+		opts[i] = f.option()
+	}
+	return opts
+}
+
+// timeZoneFunctions are the functions that take a time zone name as their
+// second argument.
+var timeZoneFunctions = map[string]bool{
+	"time.hour":          true,
+	"time.weekday":       true,
+	"time.isWeekend":     true,
+	"time.isBusinessDay": true,
+}
+
+// timeDecorator checks the constant arguments of the time functions when the
+// program is planned: literal times are parsed, and literal zone names
+// loaded, so that invalid ones fail the compilation.
+func timeDecorator(i interpreter.Interpretable) (interpreter.Interpretable, error) {
+	call, ok := i.(interpreter.InterpretableCall)
+	if !ok || !strings.HasPrefix(call.Function(), "time.") || len(call.Args()) == 0 {
+		return i, nil
+	}
+	args := call.Args()
+
+	times := args[:1]
+	if !timeZoneFunctions[call.Function()] {
+		times = args
+	}
+	for _, arg := range times {
+		if s, isConst := constString(arg); isConst {
+			if _, err := ParseTime(s); err != nil {
+				return nil, fmt.Errorf("%s(): %v", call.Function(), err)
+			}
+		}
+	}
+	if timeZoneFunctions[call.Function()] && len(args) > 1 {
+		if s, isConst := constString(args[1]); isConst {
+			if _, err := loadLocation(s); err != nil {
+				return nil, fmt.Errorf("%s(): %v", call.Function(), err)
+			}
+		}
+	}
+	return i, nil
+}
+
+func constString(i interpreter.Interpretable) (string, bool) {
+	c, isConst := i.(interpreter.InterpretableConst)
+	if !isConst {
+		return "", false
+	}
+	s, isString := c.Value().(types.String)
+	return string(s), isString
+}
+
+// Calendar is a business calendar: the days of the week that are not
+// business days, and the holidays.
+type Calendar struct {
+	weekend [7]bool
+	// dates holds the holidays of a single year, as YYYY-MM-DD, and annual
+	// the ones of every year, as MM-DD
+	dates  map[string]bool
+	annual map[string]bool
+}
+
+// NewCalendar builds a calendar from its holidays, given as YYYY-MM-DD dates
+// or as MM-DD for the holidays of every year, and its weekend days. If no
+// weekend days are given, they are Saturday and Sunday.
+func NewCalendar(holidays []string, weekend []time.Weekday) (*Calendar, error) {
+	c := &Calendar{
+		dates:  make(map[string]bool),
+		annual: make(map[string]bool),
+	}
+
+	if len(weekend) == 0 {
+		weekend = []time.Weekday{time.Saturday, time.Sunday}
+	}
+	for _, day := range weekend {
+		if day < time.Sunday || day > time.Saturday {
+			return nil, fmt.Errorf("invalid weekend day %d", day)
+		}
+		c.weekend[day] = true
+	}
+
+	for _, h := range holidays {
+		if _, err := time.Parse("2006-01-02", h); err == nil {
+			c.dates[h] = true
+			continue
+		}
+		// February 29 is a valid day of a leap year
+		if _, err := time.Parse("2006-01-02", "2000-"+h); err == nil {
+			c.annual[h] = true
+			continue
+		}
+		return nil, fmt.Errorf("invalid holiday %q, expected YYYY-MM-DD or MM-DD", h)
+	}
+	return c, nil
+}
+
+// IsBusinessDay reports whether the date of t, in its location, is neither a
+// weekend day nor a holiday.
+func (c *Calendar) IsBusinessDay(t time.Time) bool {
+	if c.weekend[t.Weekday()] {
+		return false
+	}
+	return !c.dates[t.Format("2006-01-02")] && !c.annual[t.Format("01-02")]
+}
+
+// CalendarResolver returns the calendar with the given name.
+type CalendarResolver func(name string) (*Calendar, bool)
+
+// Calendars returns the option that adds the business day function to an
+// environment:
+//
+//	time.isBusinessDay(<timestamp>, <string>, <string>) -> <bool>
+//	time.isBusinessDay(<string>, <string>, <string>) -> <bool>
+//
+// The arguments are the time, the name of the time zone its date is taken in
+// and the name of the calendar. Calendars are resolved when the expressions
+// are evaluated, so that changes to them apply without compiling the
+// expressions again; a constant calendar name that cannot be resolved fails
+// the compilation.
+func Calendars(resolve CalendarResolver) cel.EnvOption {
+	return cel.Lib(&calendarLib{resolve: resolve})
+}
+
+type calendarLib struct {
+	resolve CalendarResolver
+}
+
+func (*calendarLib) LibraryName() string {
+	return "sandrolain.rules.calendars"
+}
+
+func (lib *calendarLib) CompileOptions() []cel.EnvOption {
+	return []cel.EnvOption{
+		timeFunction{
+			name: "time.isBusinessDay", times: 1, params: []*cel.Type{cel.StringType, cel.StringType}, result: cel.BoolType,
+			impl: func(times []time.Time, args []ref.Val) ref.Val {
+				t, err := inZone(times[0], args[0])
+				if err != nil {
+					return types.WrapErr(err)
+				}
+				calendar, ok := lib.resolve(string(args[1].(types.String)))
+				if !ok {
+					return types.NewErr("unknown calendar %q", args[1])
+				}
+				return types.Bool(calendar.IsBusinessDay(t))
+			},
+		}.option(),
+	}
+}
+
+func (lib *calendarLib) ProgramOptions() []cel.ProgramOption {
+	return []cel.ProgramOption{cel.CustomDecorator(func(i interpreter.Interpretable) (interpreter.Interpretable, error) {
+		call, ok := i.(interpreter.InterpretableCall)
+		if !ok || call.Function() != "time.isBusinessDay" || len(call.Args()) != 3 {
+			return i, nil
+		}
+		if name, isConst := constString(call.Args()[2]); isConst {
+			if _, ok := lib.resolve(name); !ok {
+				return nil, fmt.Errorf("time.isBusinessDay(): unknown calendar %q", name)
+			}
+		}
+		return i, nil
+	})}
+}
@@ -73,6 +73,8 @@ func TestE2E(t *testing.T) {
 		NatsOutputSubject: "rules.engine.output",
 		NatsInputStream:   "RULES_INPUT",
 		NatsOutputStream:  "RULES_OUTPUT",
+		NatsPolicyBucket:  "RULES_POLICIES",
+		PolicySyncMode:    "local",
 		LogLevel:          "info",
 	}
 
@@ -133,15 +135,15 @@ func TestE2E(t *testing.T) {
 			Thresholds: []*api.Threshold{
 				{
 					Id:    "low",
-					Value: 5,
+					Value: proto.Float64(5),
 				},
 				{
 					Id:    "medium",
-					Value: 15,
+					Value: proto.Float64(15),
 				},
 				{
 					Id:    "high",
-					Value: 50,
+					Value: proto.Float64(50),
 				},
 			},
 		}
@@ -215,11 +217,12 @@ func TestE2E(t *testing.T) {
 		assert.Equal(t, "high", results[0].ResultThreshold)
 
 		assert.Len(t, results[0].RuleResults, 3)
-		assert.Equal(t, int64(20), results[0].RuleResults[0].Score)
+		assert.Equal(t, float64(20), results[0].RuleResults[0].Score)
+		assert.Equal(t, int64(20), results[0].RuleResults[0].LegacyScore)
 		assert.False(t, results[0].RuleResults[0].Stop)
-		assert.Equal(t, int64(40), results[0].RuleResults[1].Score)
+		assert.Equal(t, float64(40), results[0].RuleResults[1].Score)
 		assert.False(t, results[0].RuleResults[1].Stop)
-		assert.Equal(t, int64(100), results[0].RuleResults[2].Score)
+		assert.Equal(t, float64(100), results[0].RuleResults[2].Score)
 		assert.False(t, results[0].RuleResults[2].Stop)
 
 		err = msgs[0].Ack()
@@ -254,13 +257,13 @@ func TestE2E(t *testing.T) {
 		assert.Equal(t, "high", results[0].ResultThreshold)
 
 		assert.Len(t, results[0].RuleResults, 3) // Ora dovremmo avere risultati per tutte e 3 le regole
-		assert.Equal(t, int64(40), results[0].RuleResults[0].Score)
+		assert.Equal(t, float64(40), results[0].RuleResults[0].Score)
 		assert.False(t, results[0].RuleResults[0].Stop)
 		assert.True(t, results[0].RuleResults[0].Executed)
-		assert.Equal(t, int64(80), results[0].RuleResults[1].Score)
+		assert.Equal(t, float64(80), results[0].RuleResults[1].Score)
 		assert.True(t, results[0].RuleResults[1].Stop)
 		assert.True(t, results[0].RuleResults[1].Executed)
-		assert.Equal(t, int64(0), results[0].RuleResults[2].Score)
+		assert.Equal(t, float64(0), results[0].RuleResults[2].Score)
 		assert.False(t, results[0].RuleResults[2].Stop)
 		assert.False(t, results[0].RuleResults[2].Executed)
 
@@ -0,0 +1,90 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+var ErrBindingNotFound = errors.New("input binding not found")
+
+// Binding declares that the inputs published on a subject are binary
+// protobuf messages of a type from a registered descriptor set.
+type Binding struct {
+	Subject     string
+	MessageType string
+}
+
+// SetBinding binds a subject to a message type, replacing its previous
+// binding. The message type must be declared by a registered descriptor set.
+func (re *RuleEngine) SetBinding(binding Binding) error {
+	if err := re.CheckBinding(binding); err != nil {
+		return err
+	}
+
+	re.descriptorsMu.Lock()
+	defer re.descriptorsMu.Unlock()
+	re.bindings[binding.Subject] = binding
+	return nil
+}
+
+// CheckBinding checks that a binding can be set, without setting it.
+func (re *RuleEngine) CheckBinding(binding Binding) error {
+	if binding.Subject == "" {
+		return fmt.Errorf("binding subject cannot be empty")
+	}
+	_, err := re.findMessageType(binding.MessageType)
+	return err
+}
+
+func (re *RuleEngine) GetBinding(subject string) (Binding, error) {
+	re.descriptorsMu.RLock()
+	defer re.descriptorsMu.RUnlock()
+	binding, exists := re.bindings[subject]
+	if !exists {
+		return Binding{}, fmt.Errorf("%w: %s", ErrBindingNotFound, subject)
+	}
+	return binding, nil
+}
+
+func (re *RuleEngine) RemoveBinding(subject string) error {
+	re.descriptorsMu.Lock()
+	defer re.descriptorsMu.Unlock()
+	if _, exists := re.bindings[subject]; !exists {
+		return fmt.Errorf("%w: %s", ErrBindingNotFound, subject)
+	}
+	delete(re.bindings, subject)
+	return nil
+}
+
+// Bindings returns the input bindings, sorted by subject.
+func (re *RuleEngine) Bindings() []Binding {
+	re.descriptorsMu.RLock()
+	defer re.descriptorsMu.RUnlock()
+
+	bindings := make([]Binding, 0, len(re.bindings))
+	for _, binding := range re.bindings {
+		bindings = append(bindings, binding)
+	}
+	sort.Slice(bindings, func(i, j int) bool {
+		return bindings[i].Subject < bindings[j].Subject
+	})
+	return bindings
+}
+
+// DecodeMessage decodes a binary protobuf payload as a message of a type
+// from the registered descriptor sets.
+func (re *RuleEngine) DecodeMessage(messageType string, data []byte) (proto.Message, error) {
+	desc, err := re.findMessageType(messageType)
+	if err != nil {
+		return nil, err
+	}
+	msg := dynamicpb.NewMessage(desc)
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return nil, fmt.Errorf("error decoding %s message: %v", messageType, err)
+	}
+	return msg, nil
+}
@@ -0,0 +1,128 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/sandrolain/rules/models"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func TestRuleEngine_Bindings(t *testing.T) {
+	re, _ := NewRuleEngine()
+
+	binding := Binding{Subject: "rules.engine.input.orders", MessageType: "acme.Order"}
+
+	// The message type must be registered first
+	assert.Error(t, re.SetBinding(binding))
+
+	_, err := re.RegisterDescriptorSet("acme", orderDescriptorSet())
+	assert.NoError(t, err)
+	assert.NoError(t, re.SetBinding(binding))
+
+	got, err := re.GetBinding("rules.engine.input.orders")
+	assert.NoError(t, err)
+	assert.Equal(t, binding, got)
+	assert.Equal(t, []Binding{binding}, re.Bindings())
+
+	_, err = re.GetBinding("rules.engine.input")
+	assert.ErrorIs(t, err, ErrBindingNotFound)
+
+	assert.NoError(t, re.RemoveBinding("rules.engine.input.orders"))
+	assert.ErrorIs(t, re.RemoveBinding("rules.engine.input.orders"), ErrBindingNotFound)
+}
+
+func TestRuleEngine_EvaluateMessage(t *testing.T) {
+	re, _ := NewRuleEngine()
+	_, err := re.RegisterDescriptorSet("acme", orderDescriptorSet())
+	assert.NoError(t, err)
+
+	// A typed and an untyped policy evaluate the same message
+	assert.NoError(t, re.AddPolicy(models.Policy{
+		ID:          "typed",
+		Name:        "Typed",
+		InputSchema: models.InputSchema{MessageType: "acme.Order"},
+		Expression:  "input.status == 2",
+		Rules: []models.Rule{
+			{Name: "Total", Expression: "input.total * 2"},
+			{Name: "Items", Expression: "size(input.items)"},
+		},
+	}))
+	assert.NoError(t, re.AddPolicy(models.Policy{
+		ID:         "untyped",
+		Name:       "Untyped",
+		Expression: "input.status == 'STATUS_PAID'",
+		Rules: []models.Rule{
+			{Name: "Total", Expression: "input.total * 2"},
+			{Name: "Items", Expression: "size(input.items)"},
+		},
+	}))
+
+	desc, err := re.findMessageType("acme.Order")
+	assert.NoError(t, err)
+	order := dynamicpb.NewMessage(desc)
+	order.Set(desc.Fields().ByName("total"), protoreflect.ValueOfInt64(21))
+	order.Set(desc.Fields().ByName("status"), protoreflect.ValueOfEnum(2))
+	items := order.Mutable(desc.Fields().ByName("items")).List()
+	items.Append(protoreflect.ValueOfString("book"))
+	items.Append(protoreflect.ValueOfString("pen"))
+	data, err := proto.Marshal(order)
+	assert.NoError(t, err)
+
+	msg, err := re.DecodeMessage("acme.Order", data)
+	assert.NoError(t, err)
+
+	for _, id := range []string{"typed", "untyped"} {
+		_, results, err := re.Snapshot().EvaluatePolicyInput(id, models.MessageInput(msg))
+		assert.NoError(t, err, id)
+		assert.Len(t, results, 2, id)
+		assert.Equal(t, float64(42), results[0].Score, id)
+		assert.Equal(t, float64(2), results[1].Score, id)
+	}
+
+	_, err = re.DecodeMessage("acme.Order", []byte{0xff})
+	assert.Error(t, err)
+	_, err = re.DecodeMessage("acme.Unknown", data)
+	assert.Error(t, err)
+}
+
+func orderDescriptorSet() *descriptorpb.FileDescriptorSet {
+	field := func(name string, number int32, label descriptorpb.FieldDescriptorProto_Label, typ descriptorpb.FieldDescriptorProto_Type) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(number),
+			Label:    label.Enum(),
+			Type:     typ.Enum(),
+		}
+	}
+	status := field("status", 3, descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL, descriptorpb.FieldDescriptorProto_TYPE_ENUM)
+	status.TypeName = proto.String(".acme.Status")
+
+	return &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{{
+			Name:    proto.String("acme/order.proto"),
+			Package: proto.String("acme"),
+			Syntax:  proto.String("proto3"),
+			EnumType: []*descriptorpb.EnumDescriptorProto{{
+				Name: proto.String("Status"),
+				Value: []*descriptorpb.EnumValueDescriptorProto{
+					{Name: proto.String("STATUS_UNSPECIFIED"), Number: proto.Int32(0)},
+					{Name: proto.String("STATUS_NEW"), Number: proto.Int32(1)},
+					{Name: proto.String("STATUS_PAID"), Number: proto.Int32(2)},
+				},
+			}},
+			MessageType: []*descriptorpb.DescriptorProto{{
+				Name: proto.String("Order"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("total", 1, descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL, descriptorpb.FieldDescriptorProto_TYPE_INT64),
+					field("items", 2, descriptorpb.FieldDescriptorProto_LABEL_REPEATED, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+					status,
+				},
+			}},
+		}},
+	}
+}
@@ -0,0 +1,106 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	rcel "github.com/sandrolain/rules/cel"
+)
+
+var ErrCalendarNotFound = errors.New("calendar not found")
+
+// Calendar is a business calendar that expressions refer to by name, as in
+// time.isBusinessDay(input.time, 'Europe/Rome', 'it'). Holidays are dates in
+// YYYY-MM-DD format, or in MM-DD format for the holidays of every year. If
+// WeekendDays is empty, the weekend is Saturday and Sunday.
+type Calendar struct {
+	Name        string
+	Holidays    []string
+	WeekendDays []time.Weekday
+}
+
+type calendarEntry struct {
+	calendar Calendar
+	parsed   *rcel.Calendar
+}
+
+// SetCalendar sets a calendar, replacing the one with the same name. The
+// compiled expressions use the new calendar from their next evaluation.
+func (re *RuleEngine) SetCalendar(calendar Calendar) error {
+	parsed, err := parseCalendar(calendar)
+	if err != nil {
+		return err
+	}
+
+	calendar.Holidays = append([]string(nil), calendar.Holidays...)
+	calendar.WeekendDays = append([]time.Weekday(nil), calendar.WeekendDays...)
+
+	re.calendarsMu.Lock()
+	defer re.calendarsMu.Unlock()
+	re.calendars[calendar.Name] = calendarEntry{calendar: calendar, parsed: parsed}
+	return nil
+}
+
+// CheckCalendar checks that a calendar can be set, without setting it.
+func CheckCalendar(calendar Calendar) error {
+	_, err := parseCalendar(calendar)
+	return err
+}
+
+func parseCalendar(calendar Calendar) (*rcel.Calendar, error) {
+	if calendar.Name == "" {
+		return nil, fmt.Errorf("calendar name cannot be empty")
+	}
+	parsed, err := rcel.NewCalendar(calendar.Holidays, calendar.WeekendDays)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing calendar %s: %v", calendar.Name, err)
+	}
+	return parsed, nil
+}
+
+func (re *RuleEngine) GetCalendar(name string) (Calendar, error) {
+	re.calendarsMu.RLock()
+	defer re.calendarsMu.RUnlock()
+	entry, exists := re.calendars[name]
+	if !exists {
+		return Calendar{}, fmt.Errorf("%w: %s", ErrCalendarNotFound, name)
+	}
+	return entry.calendar, nil
+}
+
+// RemoveCalendar removes a calendar. The expressions that refer to it fail
+// to evaluate until a calendar with the same name is set again.
+func (re *RuleEngine) RemoveCalendar(name string) error {
+	re.calendarsMu.Lock()
+	defer re.calendarsMu.Unlock()
+	if _, exists := re.calendars[name]; !exists {
+		return fmt.Errorf("%w: %s", ErrCalendarNotFound, name)
+	}
+	delete(re.calendars, name)
+	return nil
+}
+
+// Calendars returns the calendars, sorted by name.
+func (re *RuleEngine) Calendars() []Calendar {
+	re.calendarsMu.RLock()
+	defer re.calendarsMu.RUnlock()
+
+	calendars := make([]Calendar, 0, len(re.calendars))
+	for _, entry := range re.calendars {
+		calendars = append(calendars, entry.calendar)
+	}
+	sort.Slice(calendars, func(i, j int) bool {
+		return calendars[i].Name < calendars[j].Name
+	})
+	return calendars
+}
+
+// resolveCalendar is the rcel.CalendarResolver of the engine environments.
+func (re *RuleEngine) resolveCalendar(name string) (*rcel.Calendar, bool) {
+	re.calendarsMu.RLock()
+	defer re.calendarsMu.RUnlock()
+	entry, exists := re.calendars[name]
+	return entry.parsed, exists
+}
@@ -0,0 +1,108 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sandrolain/rules/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuleEngine_Calendars(t *testing.T) {
+	re, _ := NewRuleEngine()
+
+	italy := Calendar{Name: "it", Holidays: []string{"12-25", "2024-04-01"}}
+	assert.NoError(t, re.SetCalendar(italy))
+
+	got, err := re.GetCalendar("it")
+	assert.NoError(t, err)
+	assert.Equal(t, italy, got)
+	assert.Equal(t, []Calendar{italy}, re.Calendars())
+
+	_, err = re.GetCalendar("us")
+	assert.ErrorIs(t, err, ErrCalendarNotFound)
+
+	assert.Error(t, re.SetCalendar(Calendar{Name: "invalid", Holidays: []string{"25/12"}}))
+	assert.Error(t, re.SetCalendar(Calendar{Name: "invalid", WeekendDays: []time.Weekday{7}}))
+	assert.Error(t, re.SetCalendar(Calendar{Holidays: []string{"12-25"}}))
+
+	policy := models.Policy{
+		ID:   "transfers",
+		Name: "Transfers",
+		Rules: []models.Rule{
+			{Name: "Business day", Expression: "time.isBusinessDay(input.time, 'Europe/Rome', 'it')"},
+		},
+	}
+	assert.NoError(t, re.AddPolicy(policy))
+
+	tests := []struct {
+		time     string
+		business bool
+	}{
+		{time: "2024-12-24T10:00:00+01:00", business: true},
+		{time: "2024-12-25T10:00:00+01:00", business: false},
+		{time: "2025-12-25T10:00:00+01:00", business: false},
+		{time: "2024-04-01T10:00:00+02:00", business: false},
+		{time: "2025-04-01T10:00:00+02:00", business: true},
+		// Sunday in Rome, Saturday in UTC
+		{time: "2024-12-28T23:30:00Z", business: false},
+		// Monday in Rome, Sunday in UTC
+		{time: "2024-12-29T23:30:00Z", business: true},
+	}
+	for _, tt := range tests {
+		_, results, err := re.EvaluatePolicy("transfers", map[string]interface{}{"time": tt.time})
+		assert.NoError(t, err, tt.time)
+		assert.Equal(t, tt.business, results[0].Passed, tt.time)
+	}
+
+	t.Run("Calendar changes apply without recompiling", func(t *testing.T) {
+		current, _ := re.GetPolicy("transfers")
+		assert.NoError(t, re.SetCalendar(Calendar{
+			Name:        "it",
+			WeekendDays: []time.Weekday{time.Friday, time.Saturday},
+		}))
+		updated, _ := re.GetPolicy("transfers")
+		assert.True(t, current.Rules[0].CompiledProgram == updated.Rules[0].CompiledProgram)
+
+		_, results, err := re.EvaluatePolicy("transfers", map[string]interface{}{"time": "2024-12-29T10:00:00Z"})
+		assert.NoError(t, err)
+		assert.True(t, results[0].Passed)
+	})
+
+	t.Run("Unknown calendar", func(t *testing.T) {
+		p := policy
+		p.Rules = []models.Rule{{Name: "Missing", Expression: "time.isBusinessDay(input.time, 'UTC', 'us')"}}
+		assert.ErrorContains(t, re.AddPolicy(p), "us")
+
+		assert.NoError(t, re.RemoveCalendar("it"))
+		assert.ErrorIs(t, re.RemoveCalendar("it"), ErrCalendarNotFound)
+		_, _, err := re.EvaluatePolicy("transfers", map[string]interface{}{"time": "2024-12-29T10:00:00Z"})
+		assert.ErrorContains(t, err, "unknown calendar")
+	})
+}
+
+func TestRuleEngine_TimestampInputSchema(t *testing.T) {
+	re, _ := NewRuleEngine()
+
+	err := re.AddPolicy(models.Policy{
+		ID:   "logins",
+		Name: "Logins",
+		InputSchema: models.InputSchema{JSONSchema: `{
+			"type": "object",
+			"properties": {"at": {"type": "string", "format": "date-time"}}
+		}`},
+		Rules: []models.Rule{
+			{Name: "Night", Expression: "input.at.getHours('Europe/Rome') < 6"},
+			{Name: "Weekend", Expression: "time.isWeekend(input.at, 'Europe/Rome')"},
+		},
+	})
+	assert.NoError(t, err)
+
+	_, results, err := re.EvaluatePolicy("logins", map[string]interface{}{"at": "2024-12-28T02:30:00+01:00"})
+	assert.NoError(t, err)
+	assert.True(t, results[0].Passed)
+	assert.True(t, results[1].Passed)
+
+	_, _, err = re.EvaluatePolicy("logins", map[string]interface{}{"at": "yesterday"})
+	assert.Error(t, err)
+}
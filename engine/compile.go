@@ -0,0 +1,272 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	rcel "github.com/sandrolain/rules/cel"
+	"github.com/sandrolain/rules/models"
+	"github.com/sandrolain/rules/utils"
+)
+
+// compiler checks and compiles the expressions of a policy, recording a
+// diagnostic for every problem instead of stopping at the first one. It is
+// the single pass behind both CompilePolicy and ValidatePolicy, so that they
+// cannot disagree on what a valid policy is.
+type compiler struct {
+	policyEnv *cel.Env
+	ruleEnv   *cel.Env
+	// The aggregation and decision environments are created only for the
+	// policies that need them
+	aggregationEnv func() (*cel.Env, error)
+	decisionEnv    func() (*cel.Env, error)
+	diagnostics    []Diagnostic
+}
+
+// compile checks and compiles a copy of policy. The copy can be loaded only
+// if none of the diagnostics is an error.
+//
+// The policy expression and the when expressions of the rules must return a
+// bool, and each rule expression an int, a double, a decimal, a bool or a
+// Result. Expressions typed dyn are checked when they are evaluated. The
+// score fields of the rules must be finite, and the minimum score of a rule
+// cannot be greater than its maximum score. The expression of a custom
+// aggregation must return an int, a double or a decimal. A rule has either
+// an expression or a group, whose rules are checked in the same way. The
+// names of the rules of a group, and the IDs of the thresholds, must be
+// unique.
+//
+// Programs are compiled only for the expressions that have none yet. The
+// programs of the current version of the policy are reused for the
+// expressions that did not change, so that editing a rule recompiles only
+// that rule. Changing the input schema, the geofences or the library version
+// recompiles every expression.
+func (re *RuleEngine) compile(policy models.Policy) (models.Policy, []Diagnostic) {
+	c := &compiler{policyEnv: re.policyEnv, ruleEnv: re.ruleEnv}
+	if policy.ID == "" {
+		c.report("", "id", errors.New("policy ID cannot be empty"))
+	}
+
+	// The compiled policy must not share its slices with the caller
+	policy = policy.Clone()
+	current, err := re.Snapshot().GetPolicy(policy.ID)
+	hasCurrent := err == nil
+
+	// If the library version or the input schema are not valid, the
+	// expressions are checked with the latest library or against an untyped
+	// input
+	version := policy.LibraryVersion
+	if version > rcel.LibraryVersion {
+		c.report("", "library_version", fmt.Errorf("unknown library version %d, the latest one is %d", version, rcel.LibraryVersion))
+		version = 0
+	}
+	validSchema := false
+	if err := re.resolveInputType(&policy, current, hasCurrent); err != nil {
+		c.report("", "input_schema", err)
+	} else if policyEnv, ruleEnv, err := re.envs(policy.InputType, version); err != nil {
+		c.report("", "input_schema", err)
+	} else {
+		c.policyEnv, c.ruleEnv = policyEnv, ruleEnv
+		validSchema = true
+	}
+	sameEnvs := slices.Equal(policy.Geofences, current.Geofences) && policy.LibraryVersion == current.LibraryVersion
+	if hasCurrent && !sameEnvs {
+		clearPrograms(&policy)
+	}
+	if hasCurrent && sameEnvs && validSchema && policy.InputType == current.InputType {
+		reusePrograms(&policy, current)
+	}
+
+	if len(policy.Geofences) > 0 && version != 0 && version < 3 {
+		// The geofences are part of the geospatial functions
+		c.report("", "geofences", fmt.Errorf("geofences require library version 3 or later"))
+	} else if policyEnv, ruleEnv, err := geofenceEnvs(c.policyEnv, c.ruleEnv, policy.Geofences); err != nil {
+		c.report("", "geofences", err)
+	} else {
+		c.policyEnv, c.ruleEnv = policyEnv, ruleEnv
+	}
+	c.aggregationEnv = sync.OnceValues(func() (*cel.Env, error) {
+		env, err := rcel.NewAggregationEnv(c.policyEnv)
+		if err != nil {
+			return nil, fmt.Errorf("error creating aggregation CEL environment: %v", err)
+		}
+		return env, nil
+	})
+	c.decisionEnv = sync.OnceValues(func() (*cel.Env, error) {
+		env, err := rcel.NewDecisionEnv(c.policyEnv)
+		if err != nil {
+			return nil, fmt.Errorf("error creating decision CEL environment: %v", err)
+		}
+		return env, nil
+	})
+
+	if policy.Expression != "" && policy.CompiledProgram == nil {
+		ast, program := c.expression(c.policyEnv, policy.Expression, gateOutputTypes, "", "expression")
+		if program != nil {
+			policy.CompiledProgram = program
+			policy.Explainer = models.NewExplainer(c.policyEnv, ast)
+		}
+	}
+
+	c.rules(policy.Rules, "")
+
+	if err := models.CheckOutcomeMode(policy.OutcomeMode); err != nil {
+		c.report("", "outcome_mode", err)
+	}
+	if err := policy.Aggregation.Check(); err != nil {
+		c.report("", "aggregation", err)
+	} else {
+		c.aggregation(&policy.Aggregation, "")
+	}
+
+	if err := models.CheckThresholdDirection(policy.ThresholdDirection); err != nil {
+		c.report("", "threshold_direction", err)
+	}
+	if err := models.CheckThresholds(policy.Thresholds); err != nil {
+		c.report("", "thresholds", err)
+	}
+	for i, threshold := range policy.Thresholds {
+		if threshold.Condition != "" && threshold.CompiledProgram == nil {
+			field := fmt.Sprintf("thresholds[%s].condition", threshold.ID)
+			policy.Thresholds[i].CompiledProgram = c.decisionExpression(threshold.Condition, gateOutputTypes, field)
+		}
+		for j, action := range threshold.Actions {
+			field := fmt.Sprintf("thresholds[%s].actions[%d]", threshold.ID, j)
+			if err := action.Check(); err != nil {
+				c.report("", field, err)
+			} else if action.Expression != "" && action.CompiledProgram == nil {
+				policy.Thresholds[i].Actions[j].CompiledProgram = c.decisionExpression(action.Expression, nil, field)
+			}
+		}
+	}
+
+	if policy.MaxReasons < 0 {
+		c.report("", "max_reasons", errors.New("maximum number of reasons cannot be negative"))
+	}
+	c.outputs(policy.Outputs)
+
+	sort.SliceStable(policy.Thresholds, func(i, j int) bool {
+		return policy.Thresholds[i].Position() < policy.Thresholds[j].Position()
+	})
+	return policy, c.diagnostics
+}
+
+// rules checks and compiles rules and, recursively, the rules of their
+// groups. The rules of a group are named by their path, like group/rule.
+func (c *compiler) rules(rules []models.Rule, path string) {
+	names := make(map[string]bool, len(rules))
+	for i, rule := range rules {
+		name := path + rule.Name
+		if names[rule.Name] {
+			c.report(name, "name", fmt.Errorf("duplicate rule name %s", rule.Name))
+		}
+		names[rule.Name] = true
+
+		if err := rule.CheckScore(); err != nil {
+			c.report(name, "score", err)
+		}
+		if err := rule.CheckGroup(); err != nil {
+			field := "group"
+			if rule.Group == nil {
+				field = "expression"
+			}
+			c.report(name, field, err)
+		} else if rule.Group != nil {
+			c.aggregation(&rule.Group.Aggregation, name)
+		} else if rule.CompiledProgram == nil {
+			ast, program := c.expression(c.ruleEnv, rule.Expression, ruleOutputTypes, name, "expression")
+			if program != nil {
+				rules[i].CompiledProgram = program
+				rules[i].Explainer = models.NewExplainer(c.ruleEnv, ast)
+			}
+		}
+		if rule.When != "" && rule.CompiledWhen == nil {
+			_, rules[i].CompiledWhen = c.expression(c.policyEnv, rule.When, gateOutputTypes, name, "when")
+		}
+
+		if group := rule.Group; group != nil {
+			c.rules(group.Rules, name+"/")
+			sort.SliceStable(group.Thresholds, func(i, j int) bool {
+				return group.Thresholds[i].Position() < group.Thresholds[j].Position()
+			})
+		}
+	}
+}
+
+// aggregation compiles the expression of a custom aggregation, of the policy
+// or of the group of rule.
+func (c *compiler) aggregation(aggregation *models.Aggregation, rule string) {
+	if aggregation.Mode != models.AggregationCustom || aggregation.CompiledProgram != nil {
+		return
+	}
+	env, err := c.aggregationEnv()
+	if err != nil {
+		c.report(rule, "aggregation", err)
+		return
+	}
+	_, aggregation.CompiledProgram = c.expression(env, aggregation.Expression, aggregationOutputTypes, rule, "aggregation")
+}
+
+// outputs checks that the outputs have unique names and compiles their
+// expressions.
+func (c *compiler) outputs(outputs []models.Output) {
+	names := make(map[string]bool, len(outputs))
+	for i, output := range outputs {
+		field := fmt.Sprintf("outputs[%s]", output.Name)
+		switch {
+		case output.Name == "":
+			c.report("", "outputs", errors.New("output name cannot be empty"))
+		case names[output.Name]:
+			c.report("", field, fmt.Errorf("duplicate output name %s", output.Name))
+		}
+		names[output.Name] = true
+
+		if output.Expression == "" {
+			c.report("", field, fmt.Errorf("output %s requires an expression", output.Name))
+		} else if output.CompiledProgram == nil {
+			outputs[i].CompiledProgram = c.decisionExpression(output.Expression, nil, field)
+		}
+	}
+}
+
+// decisionExpression compiles an expression of the thresholds or of the
+// outputs, evaluated once the rules are scored.
+func (c *compiler) decisionExpression(expression string, allowed []*cel.Type, field string) cel.Program {
+	env, err := c.decisionEnv()
+	if err != nil {
+		c.report("", field, err)
+		return nil
+	}
+	_, program := c.expression(env, expression, allowed, "", field)
+	return program
+}
+
+// expression compiles an expression, reporting each of its issues with its
+// position. The program is nil if there are issues.
+func (c *compiler) expression(env *cel.Env, expression string, allowed []*cel.Type, rule string, field string) (*cel.Ast, cel.Program) {
+	ast, program, issues := utils.CompileProgram(env, expression, allowed...)
+	for _, issue := range issues {
+		c.diagnostics = append(c.diagnostics, Diagnostic{
+			Rule:     rule,
+			Field:    field,
+			Line:     issue.Line,
+			Column:   issue.Column,
+			Message:  issue.Message,
+			Severity: SeverityError,
+		})
+	}
+	return ast, program
+}
+
+func (c *compiler) report(rule string, field string, err error) {
+	c.diagnostics = append(c.diagnostics, Diagnostic{
+		Rule:     rule,
+		Field:    field,
+		Message:  err.Error(),
+		Severity: SeverityError,
+	})
+}
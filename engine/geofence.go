@@ -0,0 +1,43 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	rcel "github.com/sandrolain/rules/cel"
+	"github.com/sandrolain/rules/models"
+)
+
+// geofenceEnvs parses the geofences of a policy and extends the environments
+// with them. The environments are returned as they are if there are no
+// geofences.
+func geofenceEnvs(policyEnv, ruleEnv *cel.Env, geofences []models.Geofence) (*cel.Env, *cel.Env, error) {
+	if len(geofences) == 0 {
+		return policyEnv, ruleEnv, nil
+	}
+
+	parsed := make(map[string]*rcel.Geofence, len(geofences))
+	for _, g := range geofences {
+		if g.Name == "" {
+			return nil, nil, fmt.Errorf("geofence name cannot be empty")
+		}
+		if _, exists := parsed[g.Name]; exists {
+			return nil, nil, fmt.Errorf("duplicate geofence name %s", g.Name)
+		}
+		geofence, err := rcel.ParseGeofence(g.GeoJSON)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error parsing geofence %s: %v", g.Name, err)
+		}
+		parsed[g.Name] = geofence
+	}
+
+	policyEnv, err := policyEnv.Extend(rcel.Geofences(parsed))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating policy CEL environment: %v", err)
+	}
+	ruleEnv, err = ruleEnv.Extend(rcel.Geofences(parsed))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating rule CEL environment: %v", err)
+	}
+	return policyEnv, ruleEnv, nil
+}
@@ -0,0 +1,130 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/sandrolain/rules/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// depotNorth is a square around 45.5N 9.2E with a hole around its center.
+const depotNorth = `{
+	"type": "Polygon",
+	"coordinates": [
+		[[9.1, 45.4], [9.3, 45.4], [9.3, 45.6], [9.1, 45.6], [9.1, 45.4]],
+		[[9.19, 45.49], [9.21, 45.49], [9.21, 45.51], [9.19, 45.51], [9.19, 45.49]]
+	]
+}`
+
+const depotSouth = `{
+	"type": "FeatureCollection",
+	"features": [{
+		"type": "Feature",
+		"properties": {"name": "Rome"},
+		"geometry": {
+			"type": "MultiPolygon",
+			"coordinates": [[[[12.4, 41.8], [12.6, 41.8], [12.5, 42.0], [12.4, 41.8]]]]
+		}
+	}]
+}`
+
+func TestRuleEngine_Geofences(t *testing.T) {
+	re, _ := NewRuleEngine()
+
+	policy := models.Policy{
+		ID:   "deliveries",
+		Name: "Deliveries",
+		Geofences: []models.Geofence{
+			{Name: "depot-north", GeoJSON: depotNorth},
+			{Name: "depot-south", GeoJSON: depotSouth},
+		},
+		Expression: "geofence('depot-north').contains(input.lat, input.lon) || geofence('depot-south').contains(input.lat, input.lon)",
+		Rules: []models.Rule{
+			{Name: "North", Expression: "geofence('depot-north').contains(input.lat, input.lon)"},
+			{Name: "Distance", Expression: "geo.distance(input.lat, input.lon, 45.5, 9.2) < 5000.0"},
+			{Name: "Box", Expression: "geo.inBox(input.lat, input.lon, 41.0, 12.0, 43.0, 13.0)"},
+		},
+	}
+	assert.NoError(t, re.AddPolicy(policy))
+
+	tests := []struct {
+		name     string
+		lat, lon float64
+		execute  bool
+		passed   []bool
+	}{
+		{name: "Inside the north depot", lat: 45.45, lon: 9.15, execute: true, passed: []bool{true, false, false}},
+		{name: "Inside the hole of the north depot", lat: 45.5, lon: 9.2, execute: false},
+		{name: "Inside the south depot", lat: 41.9, lon: 12.5, execute: true, passed: []bool{false, false, true}},
+		{name: "Inside the bounding box of the south depot only", lat: 41.95, lon: 12.41, execute: false},
+		{name: "Outside", lat: 40.0, lon: 10.0, execute: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := map[string]interface{}{"lat": tt.lat, "lon": tt.lon}
+			p, _ := re.GetPolicy("deliveries")
+			execute, err := p.ShouldExecute(input)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.execute, execute)
+			if !execute {
+				return
+			}
+
+			_, results, err := re.EvaluatePolicy("deliveries", input)
+			assert.NoError(t, err)
+			for i, passed := range tt.passed {
+				assert.Equal(t, passed, results[i].Passed, policy.Rules[i].Name)
+			}
+		})
+	}
+
+	t.Run("Programs recompiled when the geofences change", func(t *testing.T) {
+		current, _ := re.GetPolicy("deliveries")
+		_, err := re.ModifyPolicy("deliveries", WriteOptions{}, func(p *models.Policy) error {
+			p.Geofences = p.Geofences[:1]
+			p.Expression = "geofence('depot-north').contains(input.lat, input.lon)"
+			return nil
+		})
+		assert.NoError(t, err)
+
+		updated, _ := re.GetPolicy("deliveries")
+		assert.False(t, current.Rules[0].CompiledProgram == updated.Rules[0].CompiledProgram)
+	})
+
+	t.Run("Unknown geofence", func(t *testing.T) {
+		p := policy
+		p.Rules = []models.Rule{{Name: "Missing", Expression: "geofence('depot-east').contains(input.lat, input.lon)"}}
+		assert.ErrorContains(t, re.AddPolicy(p), "depot-east")
+	})
+
+	t.Run("Invalid geofences", func(t *testing.T) {
+		for _, geofences := range [][]models.Geofence{
+			{{Name: "open", GeoJSON: `{"type": "Polygon", "coordinates": [[[9.1, 45.4], [9.3, 45.4], [9.3, 45.6]]]}`}},
+			{{Name: "point", GeoJSON: `{"type": "Point", "coordinates": [9.1, 45.4]}`}},
+			{{Name: "invalid", GeoJSON: `not json`}},
+			{{Name: "twice", GeoJSON: depotNorth}, {Name: "twice", GeoJSON: depotSouth}},
+		} {
+			p := policy
+			p.Geofences = geofences
+			p.Expression = ""
+			p.Rules = nil
+			assert.Error(t, re.AddPolicy(p), geofences[0].Name)
+
+			diagnostics := re.ValidatePolicy(p)
+			assert.True(t, HasErrors(diagnostics))
+			assert.Equal(t, "geofences", diagnostics[0].Field)
+		}
+	})
+
+	t.Run("Policies without geofences", func(t *testing.T) {
+		p := policy
+		p.ID = "plain"
+		p.Geofences = nil
+		assert.Error(t, re.AddPolicy(p))
+
+		diagnostics := re.ValidatePolicy(p)
+		assert.True(t, HasErrors(diagnostics))
+		assert.Equal(t, "expression", diagnostics[0].Field)
+	})
+}
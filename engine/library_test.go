@@ -0,0 +1,302 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+
+	rcel "github.com/sandrolain/rules/cel"
+	"github.com/sandrolain/rules/models"
+	"github.com/sandrolain/rules/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLibrary(t *testing.T) {
+	env, err := rcel.CreateRuleEnv()
+	assert.NoError(t, err)
+
+	input := map[string]interface{}{
+		"email":   "Mario.Rossi@Example.com",
+		"amounts": []interface{}{10.0, 25.5, 4.5, 25.5},
+		"customer": map[string]interface{}{
+			"addresses": []interface{}{
+				map[string]interface{}{"city": "Rome"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		expression  string
+		expected    interface{}
+		expectError bool
+	}{
+		{name: "Strings", expression: "input.email.lowerAscii().split('@')[1]", expected: "example.com"},
+		{name: "Strings quote", expression: "strings.quote('a\"b')", expected: `"a\"b"`},
+		{name: "Encoders", expression: "base64.encode(b'rules')", expected: "cnVsZXM="},
+		{name: "Sets", expression: "sets.contains([1, 2, 3], [3, 1])", expected: true},
+		{name: "Math abs", expression: "math.abs(-5)", expected: int64(5)},
+		{name: "Math round", expression: "math.round(2.5)", expected: 3.0},
+		{name: "Clamp int", expression: "math.clamp(150, 0, 100)", expected: int64(100)},
+		{name: "Clamp double", expression: "math.clamp(-0.5, 0.0, 1.0)", expected: 0.0},
+		{name: "Clamp within range", expression: "math.clamp(42, 0, 100)", expected: int64(42)},
+		{name: "Clamp invalid range", expression: "math.clamp(1, 10, 0)", expectError: true},
+		{name: "Regex matches", expression: "regex.matches(input.email, '^[^@]+@example\\\\.com$')", expected: false},
+		{name: "Regex find", expression: "regex.find(input.email, '[A-Z][a-z]+')", expected: "Mario"},
+		{name: "Regex find all", expression: "regex.findAll('a1b22c333', '[0-9]+')", expected: []interface{}{"1", "22", "333"}},
+		{name: "Regex replace", expression: "regex.replace('2024-01-02', '-', '/')", expected: "2024/01/02"},
+		{name: "Regex invalid pattern", expression: "regex.matches('a', '(')", expectError: true},
+		{name: "Sum", expression: "[1, 2, 3].sum()", expected: int64(6)},
+		{name: "Sum of doubles", expression: "input.amounts.sum()", expected: 65.5},
+		{name: "Sum of empty list", expression: "[].sum()", expected: int64(0)},
+		{name: "Min", expression: "[3, 1, 2].min()", expected: int64(1)},
+		{name: "Max", expression: "input.amounts.max()", expected: 25.5},
+		{name: "Max of empty list", expression: "[].max()", expectError: true},
+		{name: "Avg", expression: "[1, 2, 3, 4].avg()", expected: 2.5},
+		{name: "Avg of empty list", expression: "[].avg()", expectError: true},
+		{name: "Distinct", expression: "input.amounts.distinct()", expected: []interface{}{10.0, 25.5, 4.5}},
+		{name: "Lookup", expression: "lookup(input, 'customer.addresses.0.city', 'unknown')", expected: "Rome"},
+		{name: "Lookup missing key", expression: "lookup(input, 'customer.phone.number', 'unknown')", expected: "unknown"},
+		{name: "Lookup index out of range", expression: "lookup(input, 'customer.addresses.3.city', 'unknown')", expected: "unknown"},
+		{name: "Lookup through a scalar", expression: "lookup(input, 'email.domain', 0)", expected: int64(0)},
+		{name: "Distance", expression: "math.round(geo.distance(41.9028, 12.4964, 45.4642, 9.19) / 1000.0)", expected: 477.0},
+		{name: "In box", expression: "geo.inBox(45.5, 9.2, 45.0, 9.0, 46.0, 10.0)", expected: true},
+		{name: "Not in box", expression: "geo.inBox(45.5, 8.2, 45.0, 9.0, 46.0, 10.0)", expected: false},
+		{name: "In box across the antimeridian", expression: "geo.inBox(0.0, -179.5, -1.0, 179.0, 1.0, -179.0)", expected: true},
+		{name: "Parse time", expression: "time.parse('2024-03-10') == timestamp('2024-03-10T00:00:00Z')", expected: true},
+		{name: "Parse time without offset", expression: "time.parse('2024-03-10 08:30:00').getHours()", expected: int64(8)},
+		{name: "Parse invalid time", expression: "time.parse(input.email)", expectError: true},
+		{name: "Hour in zone", expression: "time.hour('2024-07-01T22:15:00Z', 'Europe/Rome')", expected: int64(0)},
+		{name: "Hour of timestamp", expression: "time.hour(timestamp('2024-01-01T22:15:00Z'), 'America/New_York')", expected: int64(17)},
+		{name: "Weekday", expression: "time.weekday('2024-07-06T12:00:00Z', 'UTC')", expected: int64(6)},
+		{name: "Weekend", expression: "time.isWeekend('2024-07-06T23:30:00Z', 'Asia/Tokyo')", expected: true},
+		{name: "Not weekend", expression: "time.isWeekend('2024-07-07T23:30:00Z', 'Asia/Tokyo')", expected: false},
+		{name: "Age in days", expression: "time.ageDays('2024-02-28', '2024-03-01T23:00:00Z')", expected: int64(2)},
+		{name: "Age in years", expression: "time.ageYears('1990-06-15', timestamp('2024-06-14T00:00:00Z'))", expected: int64(33)},
+		{name: "Age in years on the birthday", expression: "time.ageYears('1990-06-15', '2024-06-15')", expected: int64(34)},
+		{name: "Age until now", expression: "time.ageYears('2000-01-01') >= 24 && time.ageDays('2000-01-01') > 8766", expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program, err := utils.BuildExpression(env, tt.expression, tt.name)
+			assert.NoError(t, err)
+
+			out, _, err := program.Eval(map[string]interface{}{"input": input})
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			value, err := out.ConvertToNative(reflect.TypeOf(tt.expected))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+
+	t.Run("Invalid constant times and zones fail the compilation", func(t *testing.T) {
+		for _, expression := range []string{
+			"time.hour('2024-13-01', 'UTC')",
+			"time.isWeekend(input.time, 'Europe/Atlantis')",
+			"time.ageYears(input.birth, 'tomorrow')",
+		} {
+			_, err := utils.BuildExpression(env, expression, "invalid")
+			assert.Error(t, err, expression)
+		}
+	})
+
+	t.Run("Policy environment", func(t *testing.T) {
+		env, err := rcel.CreatePolicyEnv()
+		assert.NoError(t, err)
+		_, err = utils.BuildExpression(env, "input.amounts.max() > 20.0", "policy")
+		assert.NoError(t, err)
+	})
+}
+
+func TestLibrary_Network(t *testing.T) {
+	env, err := rcel.CreateRuleEnv()
+	assert.NoError(t, err)
+
+	input := map[string]interface{}{
+		"ip":     "192.168.1.20",
+		"ipv6":   "2001:db8::1",
+		"ranges": []interface{}{"10.0.0.0/8", "192.168.0.0/16"},
+	}
+
+	tests := []struct {
+		name        string
+		expression  string
+		expected    interface{}
+		expectError bool
+	}{
+		{name: "Parse IPv4", expression: "string(ip(input.ip))", expected: "192.168.1.20"},
+		{name: "Parse IPv6", expression: "ip(input.ipv6).family()", expected: int64(6)},
+		{name: "IPv4-mapped IPv6", expression: "ip('::ffff:10.1.2.3') == ip('10.1.2.3')", expected: true},
+		{name: "Invalid IP", expression: "ip(input.ipv6 + 'x').family()", expectError: true},
+		{name: "Is IP", expression: "isIP(input.ip) && !isIP('999.1.1.1')", expected: true},
+		{name: "CIDR contains", expression: "cidr('192.168.0.0/16').contains(ip(input.ip))", expected: true},
+		{name: "CIDR not containing", expression: "cidr('10.0.0.0/8').contains(ip(input.ip))", expected: false},
+		{name: "CIDR string", expression: "string(cidr('10.1.2.3/8'))", expected: "10.0.0.0/8"},
+		{name: "Private", expression: "ip(input.ip).isPrivate() && ip('fd00::1').isPrivate()", expected: true},
+		{name: "Public", expression: "ip('8.8.8.8').isPrivate() || ip('8.8.8.8').isReserved()", expected: false},
+		{name: "Loopback", expression: "ip('127.0.0.1').isLoopback() && ip('::1').isLoopback()", expected: true},
+		{name: "Reserved", expression: "ip(input.ipv6).isReserved() && ip('169.254.1.1').isReserved()", expected: true},
+		{name: "In literal ranges", expression: "ip(input.ip).inRanges(['10.0.0.0/8', '192.168.0.0/16'])", expected: true},
+		{name: "Not in literal ranges", expression: "ip('8.8.8.8').inRanges(['10.0.0.0/8', '2001:db8::/32'])", expected: false},
+		{name: "In CIDR ranges", expression: "ip(input.ip).inRanges([cidr('192.168.1.0/24')])", expected: true},
+		{name: "In input ranges", expression: "ip(input.ip).inRanges(input.ranges)", expected: true},
+		{name: "Invalid input range", expression: "ip(input.ip).inRanges(input.ranges + ['x'])", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program, err := utils.BuildExpression(env, tt.expression, tt.name)
+			assert.NoError(t, err)
+
+			out, _, err := program.Eval(map[string]interface{}{"input": input})
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, out.Value())
+		})
+	}
+
+	t.Run("Invalid constants fail the compilation", func(t *testing.T) {
+		for _, expression := range []string{
+			"ip('10.0.0.256').isPrivate()",
+			"cidr('10.0.0.0/33').contains(ip(input.ip))",
+			"ip(input.ip).inRanges(['10.0.0.0/8', 'internal'])",
+		} {
+			_, err := utils.BuildExpression(env, expression, "invalid")
+			assert.Error(t, err, expression)
+		}
+	})
+}
+
+func TestLibrary_Decimal(t *testing.T) {
+	env, err := rcel.CreateRuleEnv()
+	assert.NoError(t, err)
+
+	amount, err := rcel.ParseDecimal("19.99")
+	assert.NoError(t, err)
+	input := map[string]interface{}{
+		"amount":   amount,
+		"quantity": int64(3),
+		"price":    "0.10",
+	}
+
+	tests := []struct {
+		name        string
+		expression  string
+		expected    string
+		expectError bool
+	}{
+		{name: "Exact sum", expression: "string(decimal('0.1') + decimal('0.2'))", expected: "0.3"},
+		{name: "Exact equality", expression: "string(decimal('0.1') + decimal('0.2') == decimal('0.3'))", expected: "true"},
+		{name: "From double", expression: "string(decimal(0.1) * decimal(3))", expected: "0.3"},
+		{name: "Input arithmetic", expression: "string(input.amount * input.quantity)", expected: "59.97"},
+		{name: "Input comparison", expression: "string(input.amount * input.quantity > 59 && decimal('0.5') < 1)", expected: "true"},
+		{name: "Input string", expression: "string(decimal(input.price) - decimal('0.01'))", expected: "0.09"},
+		{name: "Subtract", expression: "string(input.amount - decimal(20))", expected: "-0.01"},
+		{name: "Negate", expression: "string(-input.amount)", expected: "-19.99"},
+		{name: "Division", expression: "string(decimal(10) / decimal(4))", expected: "2.5"},
+		{name: "Repeating division", expression: "string(decimal(1) / decimal(3))", expected: "0.3333333333333333333333333333333333"},
+		{name: "Division by zero", expression: "string(input.amount / 0)", expectError: true},
+		{name: "Round", expression: "string(decimal('2.345').round(2))", expected: "2.35"},
+		{name: "Round negative", expression: "string(decimal('-2.5').round(0))", expected: "-3"},
+		{name: "Round half even", expression: "string(decimal('2.345').roundHalfEven(2))", expected: "2.34"},
+		{name: "Round to tens", expression: "string(decimal('1250').roundHalfEven(-2))", expected: "1200"},
+		{name: "Truncate", expression: "string(decimal('-2.349').truncate(2))", expected: "-2.34"},
+		{name: "Round to too many digits", expression: "string(input.amount.round(1000000))", expectError: true},
+		{name: "Scientific notation", expression: "string(decimal('1.5e3'))", expected: "1500"},
+		{name: "To int", expression: "string(int(input.amount))", expected: "19"},
+		{name: "To double", expression: "string(double(decimal('0.5')))", expected: "0.5"},
+		{name: "Large integers", expression: "string(decimal('9223372036854775807') + decimal(1))", expected: "9223372036854775808"},
+		{name: "Int overflow", expression: "string(int(decimal('9223372036854775808')))", expectError: true},
+		{name: "Invalid input string", expression: "string(decimal(input.price + 'x'))", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program, err := utils.BuildExpression(env, tt.expression, tt.name)
+			assert.NoError(t, err)
+
+			out, _, err := program.Eval(map[string]interface{}{"input": input})
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, out.Value())
+		})
+	}
+
+	t.Run("Invalid constants fail the compilation", func(t *testing.T) {
+		for _, expression := range []string{
+			"decimal('1.2.3') > 0",
+			"decimal('1/3') > 0",
+			"decimal('1e1000000') > 0",
+		} {
+			_, err := utils.BuildExpression(env, expression, "invalid")
+			assert.Error(t, err, expression)
+		}
+	})
+}
+
+func TestLibrary_Version(t *testing.T) {
+	re, err := NewRuleEngine()
+	assert.NoError(t, err)
+
+	policy := models.Policy{
+		ID:             "payments",
+		LibraryVersion: 1,
+		Rules:          []models.Rule{{Name: "Amount", Expression: "decimal(input.amount) > 100"}},
+	}
+
+	t.Run("Functions of later versions are rejected", func(t *testing.T) {
+		assert.Error(t, re.AddPolicy(policy))
+		diagnostics := re.ValidatePolicy(policy)
+		assert.True(t, HasErrors(diagnostics))
+		assert.Equal(t, "Amount", diagnostics[0].Rule)
+	})
+
+	t.Run("Unknown version", func(t *testing.T) {
+		unknown := policy.Clone()
+		unknown.LibraryVersion = rcel.LibraryVersion + 1
+		diagnostics := re.ValidatePolicy(unknown)
+		assert.Len(t, diagnostics, 1)
+		assert.Equal(t, "library_version", diagnostics[0].Field)
+	})
+
+	t.Run("Geofences require version 3", func(t *testing.T) {
+		geofenced := models.Policy{
+			ID:             "deliveries",
+			LibraryVersion: 2,
+			Geofences:      []models.Geofence{{Name: "depot", GeoJSON: `{"type": "Polygon", "coordinates": [[[9.1, 45.4], [9.3, 45.4], [9.3, 45.6], [9.1, 45.4]]]}`}},
+			Rules:          []models.Rule{{Name: "Near", Expression: "math.abs(input.lat) < 90.0"}},
+		}
+		diagnostics := re.ValidatePolicy(geofenced)
+		assert.True(t, HasErrors(diagnostics))
+		assert.Equal(t, "geofences", diagnostics[0].Field)
+	})
+
+	t.Run("Changing the version recompiles the policy", func(t *testing.T) {
+		latest := policy.Clone()
+		latest.LibraryVersion = 0
+		assert.NoError(t, re.AddPolicy(latest))
+		_, results, err := re.EvaluatePolicy("payments", map[string]interface{}{"amount": "150.5"})
+		assert.NoError(t, err)
+		assert.Len(t, results, 1)
+
+		pinned := latest.Clone()
+		pinned.LibraryVersion = 5
+		assert.NoError(t, re.AddPolicy(pinned))
+		assert.Error(t, re.AddPolicy(policy))
+
+		current, err := re.GetPolicy("payments")
+		assert.NoError(t, err)
+		assert.Equal(t, uint32(5), current.LibraryVersion)
+	})
+}
@@ -1,41 +1,207 @@
 package engine
 
 import (
+	"errors"
 	"fmt"
 	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	rcel "github.com/sandrolain/rules/cel"
 	"github.com/sandrolain/rules/models"
-	"github.com/sandrolain/rules/utils"
 
 	"github.com/google/cel-go/cel"
 	"github.com/google/cel-go/checker/decls"
 	"github.com/google/cel-go/common/types"
 	"github.com/google/cel-go/common/types/ref"
+	"google.golang.org/protobuf/reflect/protoregistry"
 )
 
+var (
+	ErrPolicyNotFound   = errors.New("policy not found")
+	ErrRevisionNotFound = errors.New("revision not found")
+	// ErrRevisionConflict is returned when a write expects a revision of the
+	// policy that is no longer the current one.
+	ErrRevisionConflict = errors.New("revision conflict")
+	// ErrPolicyExists is returned when a create-only write finds the policy.
+	ErrPolicyExists = errors.New("policy already exists")
+)
+
+var (
+	// gateOutputTypes are the types a policy expression may return.
+	gateOutputTypes = []*cel.Type{cel.BoolType}
+	// ruleOutputTypes are the types a rule expression may return.
+	ruleOutputTypes = []*cel.Type{cel.IntType, cel.DoubleType, rcel.DecimalType, cel.BoolType, rcel.ResultType}
+	// aggregationOutputTypes are the types an aggregation expression may
+	// return.
+	aggregationOutputTypes = []*cel.Type{cel.IntType, cel.DoubleType, rcel.DecimalType}
+)
+
+// PolicySet is an immutable snapshot of the compiled policies loaded in a
+// RuleEngine. Once published a PolicySet is never modified, so it can be
+// shared by any number of goroutines without locking.
+type PolicySet struct {
+	policies map[string]models.Policy
+}
+
+func newPolicySet(policies map[string]models.Policy) *PolicySet {
+	return &PolicySet{policies: policies}
+}
+
+func (s *PolicySet) GetPolicy(id string) (models.Policy, error) {
+	policy, exists := s.policies[id]
+	if !exists {
+		return models.Policy{}, fmt.Errorf("%w: %s", ErrPolicyNotFound, id)
+	}
+	return policy, nil
+}
+
+func (s *PolicySet) GetAllPolicies() []models.Policy {
+	policies := make([]models.Policy, 0, len(s.policies))
+	for _, policy := range s.policies {
+		policies = append(policies, policy)
+	}
+	return policies
+}
+
+func (s *PolicySet) Len() int {
+	return len(s.policies)
+}
+
+func (s *PolicySet) EvaluatePolicy(policyID string, input map[string]interface{}) (string, []models.RuleResult, error) {
+	return s.EvaluatePolicyInput(policyID, models.MapInput(input))
+}
+
+func (s *PolicySet) EvaluatePolicyInput(policyID string, input models.Input) (string, []models.RuleResult, error) {
+	result, err := s.ExecutePolicy(policyID, input)
+	if err != nil {
+		return "", nil, err
+	}
+	return result.Threshold, result.Rules, nil
+}
+
+// ExecutePolicy evaluates a policy like EvaluatePolicyInput, and also returns
+// its aggregated score. The result is empty if the policy expression is false.
+func (s *PolicySet) ExecutePolicy(policyID string, input models.Input) (models.PolicyResult, error) {
+	result, _, err := s.RunPolicy(policyID, input)
+	return result, err
+}
+
+// RunPolicy evaluates a policy like ExecutePolicy, evaluating the policy
+// expression only once, and tells whether the policy was executed.
+func (s *PolicySet) RunPolicy(policyID string, input models.Input) (models.PolicyResult, bool, error) {
+	policy, exists := s.policies[policyID]
+	if !exists {
+		return models.PolicyResult{}, false, fmt.Errorf("%w: %s", ErrPolicyNotFound, policyID)
+	}
+	return policy.Run(input)
+}
+
+// ExplainPolicy evaluates a policy like ExecutePolicy, and also explains the
+// evaluation in the Trace of the result, even if the policy expression is
+// false.
+func (s *PolicySet) ExplainPolicy(policyID string, input models.Input) (models.PolicyResult, error) {
+	policy, exists := s.policies[policyID]
+	if !exists {
+		return models.PolicyResult{}, fmt.Errorf("%w: %s", ErrPolicyNotFound, policyID)
+	}
+	return policy.ExecuteWithTrace(input)
+}
+
+// with returns a copy of the set where the given policy is added or replaced.
+func (s *PolicySet) with(policy models.Policy) *PolicySet {
+	policies := make(map[string]models.Policy, len(s.policies)+1)
+	for id, p := range s.policies {
+		policies[id] = p
+	}
+	policies[policy.ID] = policy
+	return newPolicySet(policies)
+}
+
+// without returns a copy of the set where the given policy is removed.
+func (s *PolicySet) without(id string) *PolicySet {
+	policies := make(map[string]models.Policy, len(s.policies))
+	for pid, p := range s.policies {
+		if pid != id {
+			policies[pid] = p
+		}
+	}
+	return newPolicySet(policies)
+}
+
+// RuleEngine holds the current PolicySet. Evaluations read the snapshot
+// published last and never block; management operations build a new snapshot
+// and swap it in atomically, one writer at a time.
+//
+// The engine also keeps the revision history of every policy, including the
+// deleted ones, so that a policy can be rolled back to any of its revisions.
 type RuleEngine struct {
 	policyEnv *cel.Env
 	ruleEnv   *cel.Env
-	policies  map[string]models.Policy
+	mu        sync.Mutex
+	snapshot  atomic.Pointer[PolicySet]
+	history   map[string][]models.PolicyRevision
+
+	// descriptorsMu guards the descriptor sets and the input bindings
+	descriptorsMu sync.RWMutex
+	descriptors   map[string]*protoregistry.Files
+	bindings      map[string]Binding
+
+	// calendarsMu guards the calendars, which expressions read while they
+	// are evaluated
+	calendarsMu sync.RWMutex
+	calendars   map[string]calendarEntry
+}
+
+// WriteOptions describe who changes a policy and why, and the state of the
+// policy the change is based on.
+type WriteOptions struct {
+	Author string
+	Note   string
+	// ExpectedRevision, if not zero, is the revision the policy must have
+	// for the write to succeed.
+	ExpectedRevision int64
+	// CreateOnly makes the write fail if the policy already exists.
+	CreateOnly bool
+}
+
+// checkWrite verifies the write preconditions against the policy set.
+func checkWrite(set *PolicySet, id string, opts WriteOptions) error {
+	current, exists := set.policies[id]
+	if opts.CreateOnly && exists {
+		return fmt.Errorf("%w: %s", ErrPolicyExists, id)
+	}
+	if opts.ExpectedRevision != 0 && current.Revision != opts.ExpectedRevision {
+		return fmt.Errorf("%w: policy %s is at revision %d, expected %d", ErrRevisionConflict, id, current.Revision, opts.ExpectedRevision)
+	}
+	return nil
 }
 
 func NewRuleEngine() (*RuleEngine, error) {
-	policyEnv, err := rcel.CreatePolicyEnv()
-	if err != nil {
-		return nil, fmt.Errorf("error creating policy CEL environment: %v", err)
+	re := &RuleEngine{
+		history: make(map[string][]models.PolicyRevision),
+
+		descriptors: make(map[string]*protoregistry.Files),
+		bindings:    make(map[string]Binding),
+		calendars:   make(map[string]calendarEntry),
 	}
 
-	ruleEnv, err := rcel.CreateRuleEnv()
+	policyEnv, ruleEnv, err := re.newEnvs(nil, 0)
 	if err != nil {
-		return nil, fmt.Errorf("error creating rule CEL environment: %v", err)
+		return nil, err
 	}
+	re.policyEnv, re.ruleEnv = policyEnv, ruleEnv
+
+	re.snapshot.Store(newPolicySet(make(map[string]models.Policy)))
+	return re, nil
+}
 
-	return &RuleEngine{
-		policyEnv: policyEnv,
-		ruleEnv:   ruleEnv,
-		policies:  make(map[string]models.Policy),
-	}, nil
+// Snapshot returns the current immutable set of policies. Callers that need
+// a consistent view across several calls should evaluate against a single
+// snapshot instead of going through the RuleEngine each time.
+func (re *RuleEngine) Snapshot() *PolicySet {
+	return re.snapshot.Load()
 }
 
 func CreatePolicyEnv() (*cel.Env, error) {
@@ -82,77 +248,387 @@ func CreateRuleEnv() (*cel.Env, error) {
 	)
 }
 
-func (re *RuleEngine) AddPolicy(policy models.Policy) error {
-	if policy.ID == "" {
-		return fmt.Errorf("policy ID cannot be empty")
+// CompilePolicy checks and compiles a policy without loading it in the
+// engine. The returned policy does not share its slices with the argument.
+// It fails with the first of the errors ValidatePolicy would report.
+func (re *RuleEngine) CompilePolicy(policy models.Policy) (models.Policy, error) {
+	policy, diagnostics := re.compile(policy)
+	for _, d := range diagnostics {
+		if d.Severity == SeverityError {
+			return models.Policy{}, d.err()
+		}
 	}
-	if policy.Expression != "" {
-		program, err := utils.BuildExpression(re.policyEnv, policy.Expression, policy.Name)
-		if err != nil {
-			return fmt.Errorf("error compiling policy expression: %v", err)
+	return policy, nil
+}
+
+// clearPrograms discards the compiled programs of a policy, so that all its
+// expressions are compiled again.
+func clearPrograms(policy *models.Policy) {
+	policy.CompiledProgram = nil
+	policy.Explainer = nil
+	policy.Aggregation.CompiledProgram = nil
+	clearRulePrograms(policy.Rules)
+	for i := range policy.Thresholds {
+		policy.Thresholds[i].CompiledProgram = nil
+		for j := range policy.Thresholds[i].Actions {
+			policy.Thresholds[i].Actions[j].CompiledProgram = nil
+		}
+	}
+	for i := range policy.Outputs {
+		policy.Outputs[i].CompiledProgram = nil
+	}
+}
+
+func clearRulePrograms(rules []models.Rule) {
+	for i := range rules {
+		rules[i].CompiledProgram = nil
+		rules[i].Explainer = nil
+		rules[i].CompiledWhen = nil
+		if group := rules[i].Group; group != nil {
+			group.Aggregation.CompiledProgram = nil
+			clearRulePrograms(group.Rules)
 		}
-		policy.CompiledProgram = program
 	}
+}
+
+// reusePrograms copies the programs of current to the expressions of policy
+// that are unchanged and not compiled yet.
+func reusePrograms(policy *models.Policy, current models.Policy) {
+	if policy.CompiledProgram == nil && policy.Expression == current.Expression {
+		policy.CompiledProgram = current.CompiledProgram
+		policy.Explainer = current.Explainer
+	}
+	if policy.Aggregation.CompiledProgram == nil && policy.Aggregation.Expression == current.Aggregation.Expression {
+		policy.Aggregation.CompiledProgram = current.Aggregation.CompiledProgram
+	}
+
+	reuseRulePrograms(policy.Rules, current.Rules)
 
-	// Compile all rules
-	for i, rule := range policy.Rules {
-		if rule.CompiledProgram == nil {
-			program, err := utils.BuildExpression(re.ruleEnv, rule.Expression, rule.Name)
-			if err != nil {
-				return fmt.Errorf("error compiling rule %s: %v", rule.Name, err)
+	conditions := make(map[string]models.Threshold, len(current.Thresholds))
+	for _, threshold := range current.Thresholds {
+		conditions[threshold.ID] = threshold
+	}
+	for i, threshold := range policy.Thresholds {
+		prev, ok := conditions[threshold.ID]
+		if !ok {
+			continue
+		}
+		if threshold.CompiledProgram == nil && threshold.Condition == prev.Condition {
+			policy.Thresholds[i].CompiledProgram = prev.CompiledProgram
+		}
+		// The actions are matched by position
+		for j, action := range threshold.Actions {
+			if j < len(prev.Actions) && action.CompiledProgram == nil && action.Expression == prev.Actions[j].Expression {
+				policy.Thresholds[i].Actions[j].CompiledProgram = prev.Actions[j].CompiledProgram
 			}
-			policy.Rules[i].CompiledProgram = program
 		}
 	}
 
-	// Sort thresholds
-	sort.Slice(policy.Thresholds, func(i, j int) bool {
-		return policy.Thresholds[i].Value < policy.Thresholds[j].Value
+	outputs := make(map[string]models.Output, len(current.Outputs))
+	for _, output := range current.Outputs {
+		outputs[output.Name] = output
+	}
+	for i, output := range policy.Outputs {
+		if prev, ok := outputs[output.Name]; ok && output.CompiledProgram == nil && output.Expression == prev.Expression {
+			policy.Outputs[i].CompiledProgram = prev.CompiledProgram
+		}
+	}
+}
+
+func (re *RuleEngine) AddPolicy(policy models.Policy) error {
+	_, err := re.SetPolicy(policy, WriteOptions{})
+	return err
+}
+
+// SetPolicy compiles a policy and makes it current as a new revision.
+func (re *RuleEngine) SetPolicy(policy models.Policy, opts WriteOptions) (models.PolicyRevision, error) {
+	policy, err := re.CompilePolicy(policy)
+	if err != nil {
+		return models.PolicyRevision{}, err
+	}
+
+	re.mu.Lock()
+	defer re.mu.Unlock()
+
+	current := re.snapshot.Load()
+	if err := checkWrite(current, policy.ID, opts); err != nil {
+		return models.PolicyRevision{}, err
+	}
+
+	rev := newRevision(policy, re.lastRevision(policy.ID)+1, opts)
+	re.history[policy.ID] = append(re.history[policy.ID], rev)
+	re.snapshot.Store(current.with(rev.Policy))
+	return rev, nil
+}
+
+// PrepareRevision compiles a policy and numbers it as the next revision, but
+// neither records it nor makes it current. It is used when the revision is
+// applied later, after it has gone through the policy store.
+func (re *RuleEngine) PrepareRevision(policy models.Policy, opts WriteOptions) (models.PolicyRevision, error) {
+	policy, err := re.CompilePolicy(policy)
+	if err != nil {
+		return models.PolicyRevision{}, err
+	}
+
+	re.mu.Lock()
+	defer re.mu.Unlock()
+
+	if err := checkWrite(re.snapshot.Load(), policy.ID, opts); err != nil {
+		return models.PolicyRevision{}, err
+	}
+	return newRevision(policy, re.lastRevision(policy.ID)+1, opts), nil
+}
+
+// CommitRevision records a revision returned by PrepareRevision and makes it
+// current. It fails with ErrRevisionConflict if another revision of the
+// policy was recorded since the revision was prepared.
+func (re *RuleEngine) CommitRevision(rev models.PolicyRevision) error {
+	re.mu.Lock()
+	defer re.mu.Unlock()
+
+	id := rev.Policy.ID
+	if last := re.lastRevision(id); rev.Revision != last+1 {
+		return fmt.Errorf("%w: policy %s is at revision %d, cannot commit revision %d", ErrRevisionConflict, id, last, rev.Revision)
+	}
+	re.history[id] = append(re.history[id], rev)
+	re.snapshot.Store(re.snapshot.Load().with(rev.Policy))
+	return nil
+}
+
+func newRevision(policy models.Policy, number int64, opts WriteOptions) models.PolicyRevision {
+	policy.Revision = number
+	return models.PolicyRevision{
+		Revision:  number,
+		Policy:    policy,
+		Timestamp: time.Now().UTC(),
+		Author:    opts.Author,
+		Note:      opts.Note,
+	}
+}
+
+// RecordRevision adds to the history a revision that was created elsewhere,
+// such as one restored from the policy store. Known revisions are ignored.
+func (re *RuleEngine) RecordRevision(rev models.PolicyRevision) {
+	re.mu.Lock()
+	defer re.mu.Unlock()
+
+	id := rev.Policy.ID
+	revisions := re.history[id]
+	i := sort.Search(len(revisions), func(i int) bool {
+		return revisions[i].Revision >= rev.Revision
 	})
+	if i < len(revisions) && revisions[i].Revision == rev.Revision {
+		return
+	}
+	rev.Policy.Revision = rev.Revision
+	revisions = append(revisions, models.PolicyRevision{})
+	copy(revisions[i+1:], revisions[i:])
+	revisions[i] = rev
+	re.history[id] = revisions
+}
+
+// LoadPolicy makes a stored policy current without creating a new revision:
+// the policy is the latest revision recorded in its history. A policy with
+// no recorded history becomes its revision 1.
+func (re *RuleEngine) LoadPolicy(policy models.Policy) error {
+	policy, err := re.CompilePolicy(policy)
+	if err != nil {
+		return err
+	}
+
+	re.mu.Lock()
+	defer re.mu.Unlock()
 
-	re.policies[policy.ID] = policy
+	policy.Revision = re.lastRevision(policy.ID)
+	if policy.Revision == 0 {
+		rev := newRevision(policy, 1, WriteOptions{})
+		re.history[policy.ID] = append(re.history[policy.ID], rev)
+		policy = rev.Policy
+	}
+	re.snapshot.Store(re.snapshot.Load().with(policy))
 	return nil
 }
 
-func (re *RuleEngine) GetPolicy(id string) (models.Policy, error) {
-	policy, exists := re.policies[id]
-	if !exists {
-		return models.Policy{}, fmt.Errorf("policy not found: %s", id)
+// lastRevision must be called with re.mu held.
+func (re *RuleEngine) lastRevision(id string) int64 {
+	revisions := re.history[id]
+	if len(revisions) == 0 {
+		return 0
 	}
-	return policy, nil
+	return revisions[len(revisions)-1].Revision
 }
 
-func (re *RuleEngine) EvaluatePolicy(policyID string, input map[string]interface{}) (string, []models.RuleResult, error) {
-	policy, exists := re.policies[policyID]
+// ListRevisions returns the revisions of a policy, oldest first.
+func (re *RuleEngine) ListRevisions(id string) ([]models.PolicyRevision, error) {
+	re.mu.Lock()
+	defer re.mu.Unlock()
+
+	revisions, exists := re.history[id]
 	if !exists {
-		return "", nil, fmt.Errorf("policy %s not found", policyID)
+		return nil, fmt.Errorf("%w: %s", ErrPolicyNotFound, id)
 	}
+	return append([]models.PolicyRevision(nil), revisions...), nil
+}
 
-	shouldExecute, err := policy.ShouldExecute(input)
+func (re *RuleEngine) GetRevision(id string, revision int64) (models.PolicyRevision, error) {
+	revisions, err := re.ListRevisions(id)
 	if err != nil {
-		return "", nil, fmt.Errorf("error evaluating policy expression: %v", err)
+		return models.PolicyRevision{}, err
 	}
-	if !shouldExecute {
-		return "", nil, nil
+	for _, rev := range revisions {
+		if rev.Revision == revision {
+			return rev, nil
+		}
 	}
+	return models.PolicyRevision{}, fmt.Errorf("%w: revision %d of policy %s", ErrRevisionNotFound, revision, id)
+}
 
-	return policy.Evaluate(input)
+// ModifyPolicy applies an edit to the current definition of a policy and
+// makes the result current as a new revision. Unless opts sets an expected
+// revision, the edit is retried if the policy changes in the meantime, so
+// concurrent edits to different rules are not lost.
+func (re *RuleEngine) ModifyPolicy(id string, opts WriteOptions, edit func(*models.Policy) error) (models.PolicyRevision, error) {
+	for {
+		policy, editOpts, err := re.edit(id, opts, edit)
+		if err != nil {
+			return models.PolicyRevision{}, err
+		}
+		rev, err := re.SetPolicy(policy, editOpts)
+		if errors.Is(err, ErrRevisionConflict) && opts.ExpectedRevision == 0 {
+			continue
+		}
+		return rev, err
+	}
 }
 
-func (re *RuleEngine) GetAllPolicies() []models.Policy {
-	policies := make([]models.Policy, 0, len(re.policies))
-	for _, policy := range re.policies {
-		policies = append(policies, policy)
+// PrepareModify is the PrepareRevision counterpart of ModifyPolicy.
+func (re *RuleEngine) PrepareModify(id string, opts WriteOptions, edit func(*models.Policy) error) (models.PolicyRevision, error) {
+	policy, opts, err := re.edit(id, opts, edit)
+	if err != nil {
+		return models.PolicyRevision{}, err
 	}
-	return policies
+	return re.PrepareRevision(policy, opts)
 }
 
-// Add this method to the RuleEngine struct
+// edit applies an edit to a copy of the current policy. The returned options
+// expect the revision that was edited.
+func (re *RuleEngine) edit(id string, opts WriteOptions, edit func(*models.Policy) error) (models.Policy, WriteOptions, error) {
+	set := re.Snapshot()
+	current, err := set.GetPolicy(id)
+	if err != nil {
+		return models.Policy{}, opts, err
+	}
+	if err := checkWrite(set, id, opts); err != nil {
+		return models.Policy{}, opts, err
+	}
+
+	policy := current.Clone()
+	if err := edit(&policy); err != nil {
+		return models.Policy{}, opts, err
+	}
+	opts.ExpectedRevision = current.Revision
+	opts.CreateOnly = false
+	return policy, opts, nil
+}
+
+// RollbackPolicy makes a previous revision current again. The rollback is
+// itself recorded as a new revision with the definition of the old one.
+func (re *RuleEngine) RollbackPolicy(id string, revision int64, opts WriteOptions) (models.PolicyRevision, error) {
+	policy, opts, err := re.rollback(id, revision, opts)
+	if err != nil {
+		return models.PolicyRevision{}, err
+	}
+	return re.SetPolicy(policy, opts)
+}
+
+// PrepareRollback is the PrepareRevision counterpart of RollbackPolicy.
+func (re *RuleEngine) PrepareRollback(id string, revision int64, opts WriteOptions) (models.PolicyRevision, error) {
+	policy, opts, err := re.rollback(id, revision, opts)
+	if err != nil {
+		return models.PolicyRevision{}, err
+	}
+	return re.PrepareRevision(policy, opts)
+}
+
+func (re *RuleEngine) rollback(id string, revision int64, opts WriteOptions) (models.Policy, WriteOptions, error) {
+	rev, err := re.GetRevision(id, revision)
+	if err != nil {
+		return models.Policy{}, opts, err
+	}
+	if opts.Note == "" {
+		opts.Note = fmt.Sprintf("rollback to revision %d", revision)
+	}
+	return rev.Policy, opts, nil
+}
+
+func (re *RuleEngine) GetPolicy(id string) (models.Policy, error) {
+	return re.Snapshot().GetPolicy(id)
+}
+
+func (re *RuleEngine) EvaluatePolicy(policyID string, input map[string]interface{}) (string, []models.RuleResult, error) {
+	return re.Snapshot().EvaluatePolicy(policyID, input)
+}
+
+func (re *RuleEngine) GetAllPolicies() []models.Policy {
+	return re.Snapshot().GetAllPolicies()
+}
 
 func (re *RuleEngine) DeletePolicy(id string) error {
-	if _, exists := re.policies[id]; !exists {
-		return fmt.Errorf("policy not found: %s", id)
+	return re.RemovePolicy(id, WriteOptions{})
+}
+
+// RemovePolicy deletes a policy if it matches the expected revision. The
+// revision history of the policy is kept.
+func (re *RuleEngine) RemovePolicy(id string, opts WriteOptions) error {
+	re.mu.Lock()
+	defer re.mu.Unlock()
+
+	current := re.snapshot.Load()
+	if err := checkRemove(current, id, opts); err != nil {
+		return err
 	}
-	delete(re.policies, id)
+	re.snapshot.Store(current.without(id))
 	return nil
 }
+
+// CheckRemove verifies that RemovePolicy would succeed, without deleting the
+// policy.
+func (re *RuleEngine) CheckRemove(id string, opts WriteOptions) error {
+	return checkRemove(re.Snapshot(), id, opts)
+}
+
+func checkRemove(set *PolicySet, id string, opts WriteOptions) error {
+	if _, err := set.GetPolicy(id); err != nil {
+		return err
+	}
+	return checkWrite(set, id, WriteOptions{ExpectedRevision: opts.ExpectedRevision})
+}
+
+// reuseRulePrograms copies the programs of the current rules, and of their
+// groups, to the rules with the same name.
+func reuseRulePrograms(rules []models.Rule, current []models.Rule) {
+	programs := make(map[string]models.Rule, len(current))
+	for _, rule := range current {
+		programs[rule.Name] = rule
+	}
+	for i, rule := range rules {
+		prev, ok := programs[rule.Name]
+		if !ok {
+			continue
+		}
+		if rule.CompiledProgram == nil && rule.Expression == prev.Expression {
+			rules[i].CompiledProgram = prev.CompiledProgram
+			rules[i].Explainer = prev.Explainer
+		}
+		if rule.CompiledWhen == nil && rule.When == prev.When {
+			rules[i].CompiledWhen = prev.CompiledWhen
+		}
+		if rule.Group != nil && prev.Group != nil {
+			aggregation := &rule.Group.Aggregation
+			if aggregation.CompiledProgram == nil && aggregation.Expression == prev.Group.Aggregation.Expression {
+				aggregation.CompiledProgram = prev.Group.Aggregation.CompiledProgram
+			}
+			reuseRulePrograms(rule.Group.Rules, prev.Group.Rules)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package engine
 
 import (
+	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/sandrolain/rules/cel"
@@ -15,7 +17,8 @@ func TestNewRuleEngine(t *testing.T) {
 	assert.NotNil(t, re)
 	assert.NotNil(t, re.policyEnv)
 	assert.NotNil(t, re.ruleEnv)
-	assert.NotNil(t, re.policies)
+	assert.NotNil(t, re.Snapshot())
+	assert.Equal(t, 0, re.Snapshot().Len())
 }
 
 func TestCreatePolicyEnv(t *testing.T) {
@@ -79,6 +82,42 @@ func TestRuleEngine_AddPolicy(t *testing.T) {
 			},
 			expectError: true,
 		},
+		{
+			name: "Policy with non-bool expression",
+			policy: models.Policy{
+				ID:         "policy4",
+				Name:       "NonBoolPolicy",
+				Expression: "'yes'",
+			},
+			expectError: true,
+		},
+		{
+			name: "Policy with unsupported rule type",
+			policy: models.Policy{
+				ID:         "policy5",
+				Name:       "StringRulePolicy",
+				Expression: "true",
+				Rules: []models.Rule{
+					{Name: "StringRule", Expression: "'high'"},
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "Policy with dyn expressions",
+			policy: models.Policy{
+				ID:         "policy6",
+				Name:       "DynPolicy",
+				Expression: "input.enabled",
+				Rules: []models.Rule{
+					{Name: "IntRule", Expression: "5"},
+					{Name: "DoubleRule", Expression: "input.score * 1.5"},
+					{Name: "BoolRule", Expression: "input.age > 18"},
+					{Name: "DynRule", Expression: "input.score"},
+				},
+			},
+			expectError: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -316,3 +355,695 @@ func TestRuleEngine_DeletePolicy(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestRuleEngine_Snapshot(t *testing.T) {
+	re, _ := NewRuleEngine()
+	re.AddPolicy(models.Policy{ID: "policy1", Name: "Policy1"})
+
+	snapshot := re.Snapshot()
+	re.AddPolicy(models.Policy{ID: "policy2", Name: "Policy2"})
+	re.DeletePolicy("policy1")
+
+	// A snapshot is not affected by later changes
+	assert.Equal(t, 1, snapshot.Len())
+	_, err := snapshot.GetPolicy("policy1")
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, re.Snapshot().Len())
+	_, err = re.Snapshot().GetPolicy("policy2")
+	assert.NoError(t, err)
+}
+
+func TestRuleEngine_ConcurrentAccess(t *testing.T) {
+	re, _ := NewRuleEngine()
+
+	policy := func(id string) models.Policy {
+		return models.Policy{
+			ID:   id,
+			Name: id,
+			Rules: []models.Rule{
+				{Name: "ScoreRule", Expression: "Result(input.score, false)"},
+			},
+			Thresholds: []models.Threshold{
+				{ID: "high", Value: 50},
+				{ID: "low", Value: 0},
+			},
+		}
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				id := fmt.Sprintf("policy_%d_%d", w, i%5)
+				assert.NoError(t, re.AddPolicy(policy(id)))
+				if i%3 == 0 {
+					re.DeletePolicy(id)
+				}
+			}
+		}(w)
+	}
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				set := re.Snapshot()
+				for _, p := range set.GetAllPolicies() {
+					threshold, _, err := set.EvaluatePolicy(p.ID, map[string]interface{}{"score": 60})
+					assert.NoError(t, err)
+					assert.Equal(t, "high", threshold)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestRuleEngine_Revisions(t *testing.T) {
+	re, _ := NewRuleEngine()
+
+	policy := models.Policy{
+		ID:   "test_policy",
+		Name: "TestPolicy",
+		Rules: []models.Rule{
+			{Name: "ScoreRule", Expression: "Result(10, false)"},
+		},
+	}
+	rev, err := re.SetPolicy(policy, WriteOptions{Author: "alice", Note: "first"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), rev.Revision)
+
+	policy.Rules = []models.Rule{
+		{Name: "ScoreRule", Expression: "Result(20, false)"},
+	}
+	rev, err = re.SetPolicy(policy, WriteOptions{Author: "bob"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), rev.Revision)
+
+	current, _ := re.GetPolicy("test_policy")
+	assert.Equal(t, int64(2), current.Revision)
+
+	t.Run("List revisions", func(t *testing.T) {
+		revisions, err := re.ListRevisions("test_policy")
+		assert.NoError(t, err)
+		assert.Len(t, revisions, 2)
+		assert.Equal(t, "alice", revisions[0].Author)
+		assert.Equal(t, "first", revisions[0].Note)
+		assert.Equal(t, "bob", revisions[1].Author)
+		assert.False(t, revisions[1].Timestamp.IsZero())
+
+		_, err = re.ListRevisions("non_existing_policy")
+		assert.Error(t, err)
+	})
+
+	t.Run("Get revision", func(t *testing.T) {
+		rev, err := re.GetRevision("test_policy", 1)
+		assert.NoError(t, err)
+		assert.Equal(t, "Result(10, false)", rev.Policy.Rules[0].Expression)
+
+		_, err = re.GetRevision("test_policy", 5)
+		assert.Error(t, err)
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		rev, err := re.RollbackPolicy("test_policy", 1, WriteOptions{Author: "carol"})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), rev.Revision)
+		assert.Equal(t, "rollback to revision 1", rev.Note)
+
+		threshold, results, err := re.EvaluatePolicy("test_policy", map[string]interface{}{})
+		assert.NoError(t, err)
+		assert.Equal(t, "", threshold)
+		assert.Equal(t, float64(10), results[0].Score)
+	})
+
+	t.Run("History survives deletion", func(t *testing.T) {
+		assert.NoError(t, re.DeletePolicy("test_policy"))
+		rev, err := re.RollbackPolicy("test_policy", 2, WriteOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4), rev.Revision)
+
+		_, err = re.GetPolicy("test_policy")
+		assert.NoError(t, err)
+	})
+}
+
+func TestRuleEngine_LoadPolicy(t *testing.T) {
+	re, _ := NewRuleEngine()
+
+	policy := models.Policy{ID: "test_policy", Name: "TestPolicy"}
+
+	t.Run("Without history", func(t *testing.T) {
+		assert.NoError(t, re.LoadPolicy(policy))
+		stored, _ := re.GetPolicy("test_policy")
+		assert.Equal(t, int64(1), stored.Revision)
+	})
+
+	t.Run("With recorded revisions", func(t *testing.T) {
+		re.RecordRevision(models.PolicyRevision{Revision: 3, Policy: policy})
+		re.RecordRevision(models.PolicyRevision{Revision: 2, Policy: policy})
+		re.RecordRevision(models.PolicyRevision{Revision: 3, Policy: policy})
+
+		revisions, _ := re.ListRevisions("test_policy")
+		assert.Len(t, revisions, 3)
+		for i, rev := range revisions {
+			assert.Equal(t, int64(i+1), rev.Revision)
+		}
+
+		assert.NoError(t, re.LoadPolicy(policy))
+		stored, _ := re.GetPolicy("test_policy")
+		assert.Equal(t, int64(3), stored.Revision)
+	})
+}
+
+func TestRuleEngine_CommitRevision(t *testing.T) {
+	re, _ := NewRuleEngine()
+	policy := models.Policy{ID: "test_policy", Name: "TestPolicy"}
+
+	first, err := re.PrepareRevision(policy, WriteOptions{})
+	assert.NoError(t, err)
+	second, err := re.PrepareRevision(policy, WriteOptions{})
+	assert.NoError(t, err)
+
+	// Prepared revisions are not current until they are committed
+	_, err = re.GetPolicy("test_policy")
+	assert.ErrorIs(t, err, ErrPolicyNotFound)
+
+	assert.NoError(t, re.CommitRevision(first))
+	stored, _ := re.GetPolicy("test_policy")
+	assert.Equal(t, int64(1), stored.Revision)
+
+	assert.ErrorIs(t, re.CommitRevision(second), ErrRevisionConflict)
+	revisions, _ := re.ListRevisions("test_policy")
+	assert.Len(t, revisions, 1)
+}
+
+func TestRuleEngine_OptimisticConcurrency(t *testing.T) {
+	re, _ := NewRuleEngine()
+	policy := models.Policy{ID: "test_policy", Name: "TestPolicy"}
+
+	t.Run("Create only", func(t *testing.T) {
+		_, err := re.SetPolicy(policy, WriteOptions{CreateOnly: true})
+		assert.NoError(t, err)
+
+		_, err = re.SetPolicy(policy, WriteOptions{CreateOnly: true})
+		assert.ErrorIs(t, err, ErrPolicyExists)
+	})
+
+	t.Run("Expected revision", func(t *testing.T) {
+		rev, err := re.SetPolicy(policy, WriteOptions{ExpectedRevision: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), rev.Revision)
+
+		_, err = re.SetPolicy(policy, WriteOptions{ExpectedRevision: 1})
+		assert.ErrorIs(t, err, ErrRevisionConflict)
+
+		_, err = re.PrepareRevision(policy, WriteOptions{ExpectedRevision: 1})
+		assert.ErrorIs(t, err, ErrRevisionConflict)
+
+		_, err = re.RollbackPolicy("test_policy", 1, WriteOptions{ExpectedRevision: 1})
+		assert.ErrorIs(t, err, ErrRevisionConflict)
+	})
+
+	t.Run("Remove with expected revision", func(t *testing.T) {
+		err := re.RemovePolicy("test_policy", WriteOptions{ExpectedRevision: 1})
+		assert.ErrorIs(t, err, ErrRevisionConflict)
+
+		assert.NoError(t, re.CheckRemove("test_policy", WriteOptions{ExpectedRevision: 2}))
+		assert.NoError(t, re.RemovePolicy("test_policy", WriteOptions{ExpectedRevision: 2}))
+
+		err = re.RemovePolicy("test_policy", WriteOptions{})
+		assert.ErrorIs(t, err, ErrPolicyNotFound)
+	})
+}
+
+func TestRuleEngine_ModifyPolicy(t *testing.T) {
+	re, _ := NewRuleEngine()
+	re.AddPolicy(models.Policy{
+		ID:         "test_policy",
+		Name:       "TestPolicy",
+		Expression: "true",
+		Rules: []models.Rule{
+			{Name: "Rule1", Expression: "Result(1, false)"},
+			{Name: "Rule2", Expression: "Result(2, false)"},
+		},
+	})
+	before, _ := re.GetPolicy("test_policy")
+
+	rev, err := re.ModifyPolicy("test_policy", WriteOptions{Author: "alice"}, func(p *models.Policy) error {
+		return p.UpdateRule("Rule2", models.Rule{Expression: "Result(20, false)"})
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), rev.Revision)
+
+	after, _ := re.GetPolicy("test_policy")
+	// Only the edited rule is recompiled
+	assert.True(t, before.CompiledProgram == after.CompiledProgram)
+	assert.True(t, before.Rules[0].CompiledProgram == after.Rules[0].CompiledProgram)
+	assert.False(t, before.Rules[1].CompiledProgram == after.Rules[1].CompiledProgram)
+
+	_, results, err := re.EvaluatePolicy("test_policy", map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Equal(t, float64(20), results[1].Score)
+
+	t.Run("Invalid edit", func(t *testing.T) {
+		_, err := re.ModifyPolicy("test_policy", WriteOptions{}, func(p *models.Policy) error {
+			return p.InsertRule(models.Rule{Name: "Rule3", Expression: "not valid"}, -1)
+		})
+		assert.Error(t, err)
+		current, _ := re.GetPolicy("test_policy")
+		assert.Len(t, current.Rules, 2)
+	})
+
+	t.Run("Expected revision", func(t *testing.T) {
+		_, err := re.ModifyPolicy("test_policy", WriteOptions{ExpectedRevision: 1}, func(p *models.Policy) error {
+			return p.RemoveRule("Rule1")
+		})
+		assert.ErrorIs(t, err, ErrRevisionConflict)
+	})
+
+	t.Run("Missing policy", func(t *testing.T) {
+		_, err := re.PrepareModify("non_existing_policy", WriteOptions{}, func(p *models.Policy) error {
+			return nil
+		})
+		assert.ErrorIs(t, err, ErrPolicyNotFound)
+	})
+}
+
+func TestRuleEngine_WeightedScores(t *testing.T) {
+	re, _ := NewRuleEngine()
+
+	weight, multiplier, maxScore := 0.5, 1000.0, 400.0
+	err := re.AddPolicy(models.Policy{
+		ID:   "risk",
+		Name: "Risk",
+		Rules: []models.Rule{
+			{Name: "Fraud", Expression: "input.fraud_probability", Multiplier: &multiplier, MaxScore: &maxScore},
+			{Name: "Chargeback", Expression: "input.chargeback_probability", Weight: &weight},
+		},
+		Thresholds: []models.Threshold{
+			{ID: "review", Value: 0.3},
+			{ID: "block", Value: 400.25},
+		},
+	})
+	assert.NoError(t, err)
+
+	threshold, results, err := re.EvaluatePolicy("risk", map[string]interface{}{
+		"fraud_probability":      0.9,
+		"chargeback_probability": 0.5,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 400.0, results[0].Score)
+	assert.Equal(t, 0.25, results[1].Score)
+	assert.Equal(t, "block", threshold)
+
+	threshold, _, err = re.EvaluatePolicy("risk", map[string]interface{}{
+		"fraud_probability":      0.0003,
+		"chargeback_probability": 0.1,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "review", threshold)
+
+	t.Run("Invalid score range", func(t *testing.T) {
+		minScore := 500.0
+		err := re.AddPolicy(models.Policy{
+			ID:    "invalid",
+			Rules: []models.Rule{{Name: "Rule1", Expression: "1", MinScore: &minScore, MaxScore: &maxScore}},
+		})
+		assert.Error(t, err)
+
+		diagnostics := re.ValidatePolicy(models.Policy{
+			ID:    "invalid",
+			Rules: []models.Rule{{Name: "Rule1", Expression: "1", MinScore: &minScore, MaxScore: &maxScore}},
+		})
+		assert.Len(t, diagnostics, 1)
+		assert.Equal(t, "score", diagnostics[0].Field)
+	})
+}
+
+func TestRuleEngine_Aggregation(t *testing.T) {
+	re, _ := NewRuleEngine()
+
+	policy := models.Policy{
+		ID: "worst_signal",
+		Rules: []models.Rule{
+			{Name: "Velocity", Expression: "input.velocity"},
+			{Name: "Country", Expression: "input.country == 'XX' ? 0.9 : 0.1"},
+		},
+		Thresholds:  []models.Threshold{{ID: "block", Value: 0.8}},
+		Aggregation: models.Aggregation{Mode: models.AggregationMax},
+	}
+	assert.NoError(t, re.AddPolicy(policy))
+
+	result, err := re.Snapshot().ExecutePolicy("worst_signal", models.MapInput(map[string]interface{}{"velocity": 0.3, "country": "XX"}))
+	assert.NoError(t, err)
+	assert.Equal(t, 0.9, result.Score)
+	assert.Equal(t, "block", result.Threshold)
+
+	t.Run("Custom expression", func(t *testing.T) {
+		policy.Aggregation = models.Aggregation{
+			Mode:       models.AggregationCustom,
+			Expression: "results.map(r, r.score * r.weight).sum() / double(size(results))",
+		}
+		assert.NoError(t, re.AddPolicy(policy))
+		result, err := re.Snapshot().ExecutePolicy("worst_signal", models.MapInput(map[string]interface{}{"velocity": 0.3, "country": "IT"}))
+		assert.NoError(t, err)
+		assert.InDelta(t, 0.2, result.Score, 1e-9)
+		assert.Equal(t, "", result.Threshold)
+
+		current, _ := re.GetPolicy("worst_signal")
+		assert.NotNil(t, current.Aggregation.CompiledProgram)
+	})
+
+	t.Run("Invalid aggregations", func(t *testing.T) {
+		for _, aggregation := range []models.Aggregation{
+			{Mode: "median"},
+			{Mode: models.AggregationCustom, Expression: "results.size() > 1"},
+			{Mode: models.AggregationCustom, Expression: "unknown + 1"},
+		} {
+			policy.Aggregation = aggregation
+			assert.Error(t, re.AddPolicy(policy), aggregation.Expression)
+
+			diagnostics := re.ValidatePolicy(policy)
+			assert.Len(t, diagnostics, 1, aggregation.Expression)
+			assert.Equal(t, "aggregation", diagnostics[0].Field)
+		}
+	})
+}
+
+func TestRuleEngine_Thresholds(t *testing.T) {
+	re, _ := NewRuleEngine()
+
+	policy := models.Policy{
+		ID: "credit",
+		Rules: []models.Rule{
+			{Name: "Score", Expression: "input.score"},
+			{Name: "Bankrupt", Expression: "input.bankrupt"},
+		},
+		Thresholds: []models.Threshold{
+			{ID: "good", Value: 700},
+			{ID: "fair", Value: 600},
+			{ID: "decline", Value: 0, Condition: "score < 500.0 || rules.Bankrupt.passed"},
+		},
+		ThresholdDirection: models.ThresholdDescending,
+	}
+	assert.NoError(t, re.AddPolicy(policy))
+
+	result, err := re.Snapshot().ExecutePolicy("credit", models.MapInput(map[string]interface{}{"score": 580, "bankrupt": false}))
+	assert.NoError(t, err)
+	assert.Equal(t, "fair", result.Threshold)
+
+	result, err = re.Snapshot().ExecutePolicy("credit", models.MapInput(map[string]interface{}{"score": 750, "bankrupt": true}))
+	assert.NoError(t, err)
+	assert.Equal(t, "decline", result.Threshold)
+
+	t.Run("Unchanged conditions are not recompiled", func(t *testing.T) {
+		before, _ := re.GetPolicy("credit")
+		_, err := re.ModifyPolicy("credit", WriteOptions{}, func(p *models.Policy) error {
+			return p.SetThreshold(models.Threshold{ID: "good", Value: 720})
+		})
+		assert.NoError(t, err)
+		after, _ := re.GetPolicy("credit")
+		assert.True(t, before.Thresholds[0].CompiledProgram == after.Thresholds[0].CompiledProgram)
+		assert.Equal(t, "decline", after.Thresholds[0].ID)
+	})
+
+	t.Run("Overlapping ranges are rejected", func(t *testing.T) {
+		goodMin, fairMax := 650.0, 700.0
+		_, err := re.ModifyPolicy("credit", WriteOptions{}, func(p *models.Policy) error {
+			if err := p.SetThreshold(models.Threshold{ID: "good", Range: &models.ScoreRange{Min: &goodMin}}); err != nil {
+				return err
+			}
+			return p.SetThreshold(models.Threshold{ID: "fair", Range: &models.ScoreRange{Max: &fairMax}})
+		})
+		assert.Error(t, err)
+		current, _ := re.GetPolicy("credit")
+		assert.Nil(t, current.Thresholds[1].Range)
+	})
+
+	t.Run("Invalid thresholds", func(t *testing.T) {
+		low, high := 5.0, 10.0
+		for _, thresholds := range [][]models.Threshold{
+			{{ID: "low", Range: &models.ScoreRange{Max: &low}}, {ID: "high", Range: &models.ScoreRange{Min: &high}}},
+			{{ID: "high", Value: 10}, {ID: "block", Condition: "score"}},
+			{{ID: "high", Value: 10}, {ID: "block", Condition: "rules.Unknown.passed && unknown"}},
+		} {
+			invalid := policy
+			invalid.ID = "invalid"
+			invalid.Thresholds = thresholds
+			assert.Error(t, re.AddPolicy(invalid))
+
+			diagnostics := re.ValidatePolicy(invalid)
+			assert.Len(t, diagnostics, 1)
+			assert.Contains(t, diagnostics[0].Field, "thresholds")
+		}
+	})
+}
+
+func TestRuleEngine_ThresholdActions(t *testing.T) {
+	re, _ := NewRuleEngine()
+
+	policy := models.Policy{
+		ID:    "payments",
+		Rules: []models.Rule{{Name: "Amount", Expression: "input.amount"}},
+		Thresholds: []models.Threshold{
+			{
+				ID:    "block",
+				Value: 100,
+				Actions: []models.Action{
+					{Type: models.ActionPublish, Subject: "payments.blocked", Expression: "{'id': input.id, 'amount': rules.Amount.score}"},
+					{Type: models.ActionAddTag, Name: "blocked"},
+				},
+			},
+		},
+	}
+	assert.NoError(t, re.AddPolicy(policy))
+	// The policy passed in is not modified
+	assert.Nil(t, policy.Thresholds[0].Actions[0].CompiledProgram)
+
+	result, err := re.Snapshot().ExecutePolicy("payments", models.MapInput(map[string]interface{}{"id": "p1", "amount": 150}))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"blocked"}, result.Actions.Tags)
+	if assert.Len(t, result.Actions.Messages, 1) {
+		assert.JSONEq(t, `{"id": "p1", "amount": 150}`, string(result.Actions.Messages[0].Data))
+	}
+
+	t.Run("Invalid actions", func(t *testing.T) {
+		for _, action := range []models.Action{
+			{Type: models.ActionPublish, Subject: "payments.*"},
+			{Type: models.ActionSetAttribute},
+			{Type: models.ActionPublish, Subject: "payments.blocked", Expression: "unknown.id"},
+		} {
+			invalid := policy.Clone()
+			invalid.Thresholds[0].Actions = []models.Action{action}
+			assert.Error(t, re.AddPolicy(invalid))
+
+			diagnostics := re.ValidatePolicy(invalid)
+			assert.Len(t, diagnostics, 1)
+			assert.Equal(t, "thresholds[block].actions[0]", diagnostics[0].Field)
+		}
+	})
+}
+
+func TestRuleEngine_Outputs(t *testing.T) {
+	re, _ := NewRuleEngine()
+
+	policy := models.Policy{
+		ID:    "limits",
+		Rules: []models.Rule{{Name: "Velocity", Expression: "input.velocity"}},
+		Outputs: []models.Output{
+			{Name: "limit", Expression: "score > 10.0 ? 100 : 500"},
+			{Name: "review_queue", Expression: "rules.Velocity.score > 50.0 ? 'high' : 'standard'"},
+		},
+	}
+	assert.NoError(t, re.AddPolicy(policy))
+
+	result, err := re.Snapshot().ExecutePolicy("limits", models.MapInput(map[string]interface{}{"velocity": 60}))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"limit": int64(100), "review_queue": "high"}, result.Outputs)
+
+	t.Run("Unchanged outputs are not recompiled", func(t *testing.T) {
+		before, _ := re.GetPolicy("limits")
+		edited := policy.Clone()
+		edited.Outputs[1].Expression = "'standard'"
+		assert.NoError(t, re.AddPolicy(edited))
+		after, _ := re.GetPolicy("limits")
+		assert.True(t, before.Outputs[0].CompiledProgram == after.Outputs[0].CompiledProgram)
+		assert.False(t, before.Outputs[1].CompiledProgram == after.Outputs[1].CompiledProgram)
+	})
+
+	t.Run("Invalid outputs", func(t *testing.T) {
+		for _, tt := range []struct {
+			outputs []models.Output
+			field   string
+		}{
+			{[]models.Output{{Name: "limit", Expression: "500"}, {Name: "limit", Expression: "100"}}, "outputs[limit]"},
+			{[]models.Output{{Name: "limit"}}, "outputs[limit]"},
+			{[]models.Output{{Name: "limit", Expression: "unknown * 2"}}, "outputs[limit]"},
+			{[]models.Output{{Expression: "500"}}, "outputs"},
+		} {
+			invalid := policy.Clone()
+			invalid.Outputs = tt.outputs
+			assert.Error(t, re.AddPolicy(invalid))
+
+			diagnostics := re.ValidatePolicy(invalid)
+			assert.Len(t, diagnostics, 1)
+			assert.Equal(t, tt.field, diagnostics[0].Field)
+		}
+	})
+}
+
+func TestRuleEngine_ExplainPolicy(t *testing.T) {
+	re, _ := NewRuleEngine()
+
+	assert.NoError(t, re.AddPolicy(models.Policy{
+		ID:         "payments",
+		Expression: "input.amount > 100",
+		Rules: []models.Rule{
+			{Name: "Country", Expression: "input.country == 'IT' ? 10 : 50"},
+		},
+		Thresholds: []models.Threshold{{ID: "low", Value: 0}, {ID: "high", Value: 30}},
+	}))
+
+	result, err := re.Snapshot().ExplainPolicy("payments", models.MapInput(map[string]interface{}{"amount": 150, "country": "FR"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "high", result.Threshold)
+	assert.NotNil(t, result.Trace)
+	assert.True(t, result.Trace.Executed)
+	assert.Equal(t, true, result.Trace.Gate.Value)
+	assert.Equal(t, []models.SubExpression{{Expression: "input.amount", Value: int64(150)}}, result.Trace.Gate.SubExpressions)
+	assert.Len(t, result.Trace.Rules, 1)
+	assert.Equal(t, models.RuleExecuted, result.Trace.Rules[0].Status)
+	assert.Equal(t, int64(50), result.Trace.Rules[0].Expression.Value)
+	assert.Contains(t, result.Trace.Rules[0].Expression.SubExpressions,
+		models.SubExpression{Expression: "input.country == \"IT\"", Value: false})
+	assert.Len(t, result.Trace.Thresholds, 2)
+
+	t.Run("Policy expression is false", func(t *testing.T) {
+		result, err := re.Snapshot().ExplainPolicy("payments", models.MapInput(map[string]interface{}{"amount": 50, "country": "FR"}))
+		assert.NoError(t, err)
+		assert.Empty(t, result.Threshold)
+		assert.False(t, result.Trace.Executed)
+		assert.Equal(t, false, result.Trace.Gate.Value)
+		assert.Empty(t, result.Trace.Rules)
+	})
+
+	_, err = re.Snapshot().ExplainPolicy("missing", models.MapInput(nil))
+	assert.ErrorIs(t, err, ErrPolicyNotFound)
+}
+
+func TestRuleEngine_RuleWhen(t *testing.T) {
+	re, _ := NewRuleEngine()
+
+	policy := models.Policy{
+		ID: "products",
+		Rules: []models.Rule{
+			{Name: "Italy", Expression: "20", When: "input.country == 'IT'"},
+			{Name: "Online", Expression: "Result(50, true)", When: "input.channel == 'web'"},
+			{Name: "Base", Expression: "1"},
+		},
+		Thresholds: []models.Threshold{{ID: "low", Value: 0}, {ID: "high", Value: 20}},
+	}
+	assert.NoError(t, re.AddPolicy(policy))
+
+	result, err := re.Snapshot().ExecutePolicy("products", models.MapInput(map[string]interface{}{"country": "IT", "channel": "store"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "high", result.Threshold)
+	assert.Equal(t, 21.0, result.Score)
+	assert.True(t, result.Rules[1].NotApplicable)
+	assert.False(t, result.Rules[1].Executed)
+
+	result, err = re.Snapshot().ExecutePolicy("products", models.MapInput(map[string]interface{}{"country": "FR", "channel": "web"}))
+	assert.NoError(t, err)
+	assert.Equal(t, 50.0, result.Score)
+	assert.True(t, result.Rules[0].NotApplicable)
+	// The rule stopped by Online is not executed, but it is applicable
+	assert.False(t, result.Rules[2].Executed)
+	assert.False(t, result.Rules[2].NotApplicable)
+
+	t.Run("Unchanged when expressions are not recompiled", func(t *testing.T) {
+		before, _ := re.GetPolicy("products")
+		edited := policy.Clone()
+		edited.Rules[1].When = "input.channel == 'app'"
+		assert.NoError(t, re.AddPolicy(edited))
+		after, _ := re.GetPolicy("products")
+		assert.True(t, before.Rules[0].CompiledWhen == after.Rules[0].CompiledWhen)
+		assert.False(t, before.Rules[1].CompiledWhen == after.Rules[1].CompiledWhen)
+	})
+}
+
+func TestRuleEngine_RuleGroups(t *testing.T) {
+	re, _ := NewRuleEngine()
+
+	half := 0.5
+	policy := models.Policy{
+		ID: "products",
+		Rules: []models.Rule{
+			{Name: "Payments", When: "input.channel == 'web'", Group: &models.RuleGroup{
+				Rules: []models.Rule{
+					{Name: "Amount", Expression: "input.amount / 10"},
+					{Name: "Cards", Group: &models.RuleGroup{
+						Rules: []models.Rule{
+							{Name: "Prepaid", Expression: "input.prepaid ? 40 : 0"},
+							{Name: "Foreign", Expression: "input.foreign ? 20 : 0"},
+						},
+						Aggregation: models.Aggregation{Mode: models.AggregationCustom, Expression: "results.map(r, r.score).max()"},
+					}},
+				},
+				Thresholds: []models.Threshold{{ID: "high", Value: 50}, {ID: "low", Value: 0}},
+			}, Weight: &half},
+			{Name: "Base", Expression: "1"},
+		},
+	}
+	assert.NoError(t, re.AddPolicy(policy))
+
+	input := map[string]interface{}{"channel": "web", "amount": 200, "prepaid": true, "foreign": true}
+	result, err := re.Snapshot().ExecutePolicy("products", models.MapInput(input))
+	assert.NoError(t, err)
+	payments := result.Rules[0]
+	assert.Equal(t, "high", payments.Threshold)
+	assert.Equal(t, 30.0, payments.Score)
+	assert.Equal(t, 40.0, payments.Rules[1].Score)
+	assert.Len(t, payments.Rules[1].Rules, 2)
+	assert.Equal(t, 31.0, result.Score)
+
+	input["channel"] = "store"
+	result, err = re.Snapshot().ExecutePolicy("products", models.MapInput(input))
+	assert.NoError(t, err)
+	assert.True(t, result.Rules[0].NotApplicable)
+	assert.Equal(t, 1.0, result.Score)
+
+	t.Run("Unchanged rules of groups are not recompiled", func(t *testing.T) {
+		before, _ := re.GetPolicy("products")
+		edited := policy.Clone()
+		edited.Rules[0].Group.Rules[1].Group.Rules[1].Expression = "input.foreign ? 30 : 0"
+		assert.NoError(t, re.AddPolicy(edited))
+		after, _ := re.GetPolicy("products")
+		beforeCards, afterCards := before.Rules[0].Group.Rules[1].Group, after.Rules[0].Group.Rules[1].Group
+		assert.True(t, before.Rules[0].Group.Rules[0].CompiledProgram == after.Rules[0].Group.Rules[0].CompiledProgram)
+		assert.True(t, beforeCards.Aggregation.CompiledProgram == afterCards.Aggregation.CompiledProgram)
+		assert.True(t, beforeCards.Rules[0].CompiledProgram == afterCards.Rules[0].CompiledProgram)
+		assert.False(t, beforeCards.Rules[1].CompiledProgram == afterCards.Rules[1].CompiledProgram)
+		assert.Equal(t, "input.foreign ? 20 : 0", policy.Rules[0].Group.Rules[1].Group.Rules[1].Expression)
+	})
+
+	t.Run("Invalid groups", func(t *testing.T) {
+		invalid := policy.Clone()
+		invalid.Rules[0].Group.Rules[1].Group.Rules[0].Expression = "unknown"
+		assert.Error(t, re.AddPolicy(invalid))
+		diagnostics := re.ValidatePolicy(invalid)
+		assert.Len(t, diagnostics, 1)
+		assert.Equal(t, "Payments/Cards/Prepaid", diagnostics[0].Rule)
+		assert.Equal(t, "expression", diagnostics[0].Field)
+
+		invalid = policy.Clone()
+		invalid.Rules[0].Expression = "1"
+		assert.Error(t, re.AddPolicy(invalid))
+		diagnostics = re.ValidatePolicy(invalid)
+		assert.Len(t, diagnostics, 1)
+		assert.Equal(t, "group", diagnostics[0].Field)
+	})
+}
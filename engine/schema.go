@@ -0,0 +1,251 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/google/cel-go/cel"
+	rcel "github.com/sandrolain/rules/cel"
+	"github.com/sandrolain/rules/models"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+var ErrDescriptorSetNotFound = errors.New("descriptor set not found")
+
+// DescriptorSet is a registered set of protobuf files, with the full names of
+// the message types they declare.
+type DescriptorSet struct {
+	Name         string
+	MessageTypes []string
+}
+
+// RegisterDescriptorSet registers a set of protobuf files under a name, so
+// that policies can declare one of their message types as the type of their
+// input. Registering a name again replaces its files; the policies already
+// compiled keep the types they were compiled with until they are set again.
+//
+// The files must follow the files they import, as in the sets written by
+// protoc --include_imports. The imports of the well-known types may be left
+// out of the set.
+func (re *RuleEngine) RegisterDescriptorSet(name string, set *descriptorpb.FileDescriptorSet) (DescriptorSet, error) {
+	files, err := newDescriptorFiles(set)
+	if err != nil {
+		return DescriptorSet{}, err
+	}
+
+	re.descriptorsMu.Lock()
+	defer re.descriptorsMu.Unlock()
+	re.descriptors[name] = files
+	return DescriptorSet{Name: name, MessageTypes: messageTypes(files)}, nil
+}
+
+// CheckDescriptorSet checks that a set of protobuf files can be registered,
+// without registering it.
+func CheckDescriptorSet(name string, set *descriptorpb.FileDescriptorSet) (DescriptorSet, error) {
+	files, err := newDescriptorFiles(set)
+	if err != nil {
+		return DescriptorSet{}, err
+	}
+	return DescriptorSet{Name: name, MessageTypes: messageTypes(files)}, nil
+}
+
+func (re *RuleEngine) GetDescriptorSet(name string) (DescriptorSet, error) {
+	re.descriptorsMu.RLock()
+	defer re.descriptorsMu.RUnlock()
+	files, exists := re.descriptors[name]
+	if !exists {
+		return DescriptorSet{}, fmt.Errorf("%w: %s", ErrDescriptorSetNotFound, name)
+	}
+	return DescriptorSet{Name: name, MessageTypes: messageTypes(files)}, nil
+}
+
+func (re *RuleEngine) RemoveDescriptorSet(name string) error {
+	re.descriptorsMu.Lock()
+	defer re.descriptorsMu.Unlock()
+	if _, exists := re.descriptors[name]; !exists {
+		return fmt.Errorf("%w: %s", ErrDescriptorSetNotFound, name)
+	}
+	delete(re.descriptors, name)
+	return nil
+}
+
+// DescriptorSets returns the registered descriptor sets, sorted by name.
+func (re *RuleEngine) DescriptorSets() []DescriptorSet {
+	re.descriptorsMu.RLock()
+	defer re.descriptorsMu.RUnlock()
+
+	sets := make([]DescriptorSet, 0, len(re.descriptors))
+	for name, files := range re.descriptors {
+		sets = append(sets, DescriptorSet{Name: name, MessageTypes: messageTypes(files)})
+	}
+	sort.Slice(sets, func(i, j int) bool {
+		return sets[i].Name < sets[j].Name
+	})
+	return sets
+}
+
+// findMessageType looks for a message type in the registered descriptor
+// sets, in name order.
+func (re *RuleEngine) findMessageType(name string) (protoreflect.MessageDescriptor, error) {
+	re.descriptorsMu.RLock()
+	defer re.descriptorsMu.RUnlock()
+
+	names := make([]string, 0, len(re.descriptors))
+	for n := range re.descriptors {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	for _, n := range names {
+		desc, err := re.descriptors[n].FindDescriptorByName(protoreflect.FullName(name))
+		if err != nil {
+			continue
+		}
+		if msg, ok := desc.(protoreflect.MessageDescriptor); ok {
+			return msg, nil
+		}
+	}
+	return nil, fmt.Errorf("message type %s is not declared by any registered descriptor set", name)
+}
+
+// resolveInputType sets the input type of a policy from its input schema. If
+// the type changes, the programs of the policy are dropped, since they were
+// compiled for another type.
+//
+// Message types are looked up in the registered descriptor sets every time,
+// so that setting the policy again picks up a replaced set. The type built
+// from a JSON schema is kept if the policy already has one built from the
+// same schema, or taken from the current version of the policy if the schema
+// did not change.
+func (re *RuleEngine) resolveInputType(policy *models.Policy, current models.Policy, hasCurrent bool) error {
+	schema := policy.InputSchema
+	var inputType protoreflect.MessageDescriptor
+	switch {
+	case schema.IsZero():
+	case schema.JSONSchema != "" && schema.MessageType != "":
+		return fmt.Errorf("input schema cannot have both a JSON schema and a message type")
+	case schema.MessageType != "":
+		t, err := re.findMessageType(schema.MessageType)
+		if err != nil {
+			return fmt.Errorf("error resolving input schema: %v", err)
+		}
+		inputType = t
+	case policy.InputType != nil && policy.InputTypeSchema == schema:
+		inputType = policy.InputType
+	case hasCurrent && current.InputType != nil && current.InputSchema == schema:
+		inputType = current.InputType
+	default:
+		t, err := rcel.JSONSchemaMessage(schema.JSONSchema)
+		if err != nil {
+			return fmt.Errorf("error resolving input schema: %v", err)
+		}
+		inputType = t
+	}
+
+	if inputType != policy.InputType {
+		policy.InputType = inputType
+		clearPrograms(policy)
+	}
+	policy.InputTypeSchema = schema
+	return nil
+}
+
+// envs returns the environments where the expressions of a policy with the
+// given input type and library version are compiled.
+func (re *RuleEngine) envs(inputType protoreflect.MessageDescriptor, version uint32) (*cel.Env, *cel.Env, error) {
+	if inputType == nil && (version == 0 || version == rcel.LibraryVersion) {
+		return re.policyEnv, re.ruleEnv, nil
+	}
+	return re.newEnvs(inputType, version)
+}
+
+// newEnvs creates the policy and rule environments for an input type and a
+// version of the function library, the latest one if version is 0. The
+// calendars of the engine are available from version 4, which added the time
+// functions.
+func (re *RuleEngine) newEnvs(inputType protoreflect.MessageDescriptor, version uint32) (*cel.Env, *cel.Env, error) {
+	if version == 0 {
+		version = rcel.LibraryVersion
+	}
+	opts := []cel.EnvOption{rcel.Library(version)}
+	if version >= 4 {
+		opts = append(opts, rcel.Calendars(re.resolveCalendar))
+	}
+	policyEnv, err := rcel.NewPolicyEnv(inputType, opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating policy CEL environment: %v", err)
+	}
+	ruleEnv, err := rcel.NewRuleEnv(inputType, opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating rule CEL environment: %v", err)
+	}
+	return policyEnv, ruleEnv, nil
+}
+
+func newDescriptorFiles(set *descriptorpb.FileDescriptorSet) (*protoregistry.Files, error) {
+	files := new(protoregistry.Files)
+	for _, fdp := range set.GetFile() {
+		// The files linked into the engine, such as the well-known types,
+		// are used as they are
+		if _, err := protoregistry.GlobalFiles.FindFileByPath(fdp.GetName()); err == nil {
+			continue
+		}
+		fd, err := protodesc.NewFile(fdp, descriptorResolver{files})
+		if err != nil {
+			return nil, fmt.Errorf("error building file %s: %v", fdp.GetName(), err)
+		}
+		if err := files.RegisterFile(fd); err != nil {
+			return nil, fmt.Errorf("error registering file %s: %v", fdp.GetName(), err)
+		}
+	}
+	if files.NumFiles() == 0 {
+		return nil, fmt.Errorf("the descriptor set has no files")
+	}
+	return files, nil
+}
+
+func messageTypes(files *protoregistry.Files) []string {
+	var names []string
+	var collect func(msgs protoreflect.MessageDescriptors)
+	collect = func(msgs protoreflect.MessageDescriptors) {
+		for i := 0; i < msgs.Len(); i++ {
+			msg := msgs.Get(i)
+			if msg.IsMapEntry() {
+				continue
+			}
+			names = append(names, string(msg.FullName()))
+			collect(msg.Messages())
+		}
+	}
+	files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		collect(fd.Messages())
+		return true
+	})
+	sort.Strings(names)
+	return names
+}
+
+// descriptorResolver resolves the imports of the files of a descriptor set
+// from the files of the set built so far, then from the files linked into
+// the engine.
+type descriptorResolver struct {
+	files *protoregistry.Files
+}
+
+func (r descriptorResolver) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	if fd, err := r.files.FindFileByPath(path); err == nil {
+		return fd, nil
+	}
+	return protoregistry.GlobalFiles.FindFileByPath(path)
+}
+
+func (r descriptorResolver) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	if desc, err := r.files.FindDescriptorByName(name); err == nil {
+		return desc, nil
+	}
+	return protoregistry.GlobalFiles.FindDescriptorByName(name)
+}
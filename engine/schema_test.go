@@ -0,0 +1,198 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/sandrolain/rules/models"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const paymentSchema = `{
+	"type": "object",
+	"required": ["amount"],
+	"properties": {
+		"amount": {"type": "number"},
+		"count": {"type": "integer"},
+		"country": {"type": "string"},
+		"tags": {"type": "array", "items": {"type": "string"}},
+		"customer": {
+			"type": "object",
+			"properties": {
+				"age": {"type": "integer"},
+				"vip": {"type": "boolean"}
+			}
+		},
+		"limits": {"type": "object", "additionalProperties": {"type": "number"}},
+		"extra": {}
+	}
+}`
+
+func TestRuleEngine_JSONInputSchema(t *testing.T) {
+	re, _ := NewRuleEngine()
+
+	policy := models.Policy{
+		ID:          "payments",
+		Name:        "Payments",
+		InputSchema: models.InputSchema{JSONSchema: paymentSchema},
+		Expression:  "input.amount > 0.0",
+		Rules: []models.Rule{
+			{Name: "Amount", Expression: "input.amount > 1000.0 ? Result(50, false) : Result(0, false)"},
+			{Name: "Customer", Expression: "input.customer.vip ? -10 : input.customer.age"},
+			{Name: "Tags", Expression: "'risky' in input.tags"},
+			{Name: "Limits", Expression: "input.limits['daily'] < input.amount"},
+			{Name: "Extra", Expression: "input.extra.level == 'high'"},
+		},
+	}
+
+	t.Run("Valid expressions", func(t *testing.T) {
+		assert.NoError(t, re.AddPolicy(policy))
+
+		threshold, results, err := re.EvaluatePolicy("payments", map[string]interface{}{
+			"amount":   1500.0,
+			"tags":     []interface{}{"risky"},
+			"customer": map[string]interface{}{"age": 30.0, "vip": false},
+			"limits":   map[string]interface{}{"daily": 1000.0},
+			"extra":    map[string]interface{}{"level": "high"},
+			"unknown":  "ignored",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "", threshold)
+		assert.Equal(t, float64(50), results[0].Score)
+		assert.Equal(t, float64(30), results[1].Score)
+		assert.True(t, results[2].Passed)
+		assert.True(t, results[3].Passed)
+		assert.True(t, results[4].Passed)
+	})
+
+	t.Run("Input not matching the schema", func(t *testing.T) {
+		_, _, err := re.EvaluatePolicy("payments", map[string]interface{}{"amount": "a lot"})
+		assert.Error(t, err)
+
+		// amount is required
+		_, _, err = re.EvaluatePolicy("payments", map[string]interface{}{"country": "IT"})
+		assert.Error(t, err)
+	})
+
+	t.Run("Unknown field", func(t *testing.T) {
+		p := policy
+		p.Rules = []models.Rule{{Name: "Typo", Expression: "input.ammount > 1000.0"}}
+		err := re.AddPolicy(p)
+		assert.ErrorContains(t, err, "ammount")
+	})
+
+	t.Run("Type mismatch", func(t *testing.T) {
+		p := policy
+		p.Rules = []models.Rule{{Name: "Mismatch", Expression: "input.country > 10"}}
+		assert.Error(t, re.AddPolicy(p))
+	})
+
+	t.Run("Programs reused while the schema is unchanged", func(t *testing.T) {
+		current, _ := re.GetPolicy("payments")
+		_, err := re.ModifyPolicy("payments", WriteOptions{}, func(p *models.Policy) error {
+			return p.SetRuleEnabled("Tags", false)
+		})
+		assert.NoError(t, err)
+
+		updated, _ := re.GetPolicy("payments")
+		assert.True(t, current.InputType == updated.InputType)
+		assert.True(t, current.Rules[0].CompiledProgram == updated.Rules[0].CompiledProgram)
+	})
+
+	t.Run("Edited schema of a compiled policy", func(t *testing.T) {
+		// The copy carries the type built from the previous schema
+		edited, _ := re.GetPolicy("payments")
+		assert.NotNil(t, edited.InputType)
+		edited.InputSchema = models.InputSchema{JSONSchema: `{"type": "object", "properties": {"channel": {"type": "string"}}}`}
+		edited.Expression = ""
+		edited.Rules = []models.Rule{{Name: "Channel", Expression: "input.channel == 'web'"}}
+		compiled, err := re.CompilePolicy(edited)
+		assert.NoError(t, err)
+		assert.False(t, edited.InputType == compiled.InputType)
+
+		// A compiled copy keeps its type while its schema is unchanged, and
+		// drops it when the schema is edited again
+		again, err := re.CompilePolicy(compiled)
+		assert.NoError(t, err)
+		assert.True(t, compiled.InputType == again.InputType)
+		again.InputSchema = policy.InputSchema
+		_, err = re.CompilePolicy(again)
+		assert.ErrorContains(t, err, "channel")
+	})
+
+	t.Run("Invalid schema", func(t *testing.T) {
+		p := policy
+		p.ID = "invalid"
+		p.InputSchema = models.InputSchema{JSONSchema: `{"type": "array"}`}
+		assert.Error(t, re.AddPolicy(p))
+
+		diagnostics := re.ValidatePolicy(p)
+		assert.True(t, HasErrors(diagnostics))
+		assert.Equal(t, "input_schema", diagnostics[0].Field)
+	})
+
+	t.Run("Validation reports unknown fields", func(t *testing.T) {
+		p := policy
+		p.Rules = []models.Rule{{Name: "Typo", Expression: "input.ammount > 1000.0"}}
+		diagnostics := re.ValidatePolicy(p)
+		assert.Len(t, diagnostics, 1)
+		assert.Equal(t, "Typo", diagnostics[0].Rule)
+		assert.Equal(t, 6, diagnostics[0].Column)
+	})
+}
+
+func TestRuleEngine_MessageInputSchema(t *testing.T) {
+	re, _ := NewRuleEngine()
+
+	set := &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{{
+			Name:    proto.String("acme/order.proto"),
+			Package: proto.String("acme"),
+			Syntax:  proto.String("proto3"),
+			MessageType: []*descriptorpb.DescriptorProto{{
+				Name: proto.String("Order"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{
+						Name:     proto.String("total"),
+						JsonName: proto.String("total"),
+						Number:   proto.Int32(1),
+						Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+						Type:     descriptorpb.FieldDescriptorProto_TYPE_INT64.Enum(),
+					},
+				},
+			}},
+		}},
+	}
+
+	policy := models.Policy{
+		ID:          "orders",
+		Name:        "Orders",
+		InputSchema: models.InputSchema{MessageType: "acme.Order"},
+		Rules: []models.Rule{
+			{Name: "Total", Expression: "input.total * 2"},
+		},
+	}
+
+	// The message type must be registered first
+	assert.Error(t, re.AddPolicy(policy))
+
+	registered, err := re.RegisterDescriptorSet("acme", set)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"acme.Order"}, registered.MessageTypes)
+	assert.Equal(t, []DescriptorSet{registered}, re.DescriptorSets())
+
+	assert.NoError(t, re.AddPolicy(policy))
+	_, results, err := re.EvaluatePolicy("orders", map[string]interface{}{"total": 21.0})
+	assert.NoError(t, err)
+	assert.Equal(t, float64(42), results[0].Score)
+
+	policy.Rules = []models.Rule{{Name: "Missing", Expression: "input.discount"}}
+	assert.Error(t, re.AddPolicy(policy))
+
+	assert.NoError(t, re.RemoveDescriptorSet("acme"))
+	assert.ErrorIs(t, re.RemoveDescriptorSet("acme"), ErrDescriptorSetNotFound)
+
+	_, err = re.RegisterDescriptorSet("empty", &descriptorpb.FileDescriptorSet{})
+	assert.Error(t, err)
+}
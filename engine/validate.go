@@ -0,0 +1,58 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/sandrolain/rules/models"
+)
+
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+)
+
+// Diagnostic is a problem found validating a policy. Rule is empty for the
+// problems of the policy itself, and Field names the definition field the
+// problem refers to. Line and Column are 1-based positions in the expression
+// source, or 0 when the problem has no position.
+type Diagnostic struct {
+	Rule     string
+	Field    string
+	Line     int
+	Column   int
+	Message  string
+	Severity Severity
+}
+
+// ValidatePolicy compiles a policy with the same environments and checks
+// used by CompilePolicy, but does not load it. It reports all the problems
+// found instead of stopping at the first one. The policy is valid if none of
+// the diagnostics is an error.
+func (re *RuleEngine) ValidatePolicy(policy models.Policy) []Diagnostic {
+	_, diagnostics := re.compile(policy)
+	return diagnostics
+}
+
+// err returns the diagnostic as the error of CompilePolicy.
+func (d Diagnostic) err() error {
+	where := d.Field
+	if d.Rule != "" {
+		where += " of rule " + d.Rule
+	}
+	if d.Line > 0 {
+		where += fmt.Sprintf(" at %d:%d", d.Line, d.Column)
+	}
+	return fmt.Errorf("invalid %s: %s", where, d.Message)
+}
+
+// HasErrors reports whether any of the diagnostics is an error.
+func HasErrors(diagnostics []Diagnostic) bool {
+	for _, d := range diagnostics {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,175 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/sandrolain/rules/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuleEngine_ValidatePolicy(t *testing.T) {
+	re, _ := NewRuleEngine()
+
+	t.Run("Valid policy", func(t *testing.T) {
+		diagnostics := re.ValidatePolicy(models.Policy{
+			ID:         "policy1",
+			Name:       "ValidPolicy",
+			Expression: "input.age >= 18",
+			Rules: []models.Rule{
+				{Name: "Rule1", Expression: "Result(10, false)"},
+			},
+		})
+		assert.Empty(t, diagnostics)
+		assert.False(t, HasErrors(diagnostics))
+
+		// Validation does not load the policy
+		_, err := re.GetPolicy("policy1")
+		assert.ErrorIs(t, err, ErrPolicyNotFound)
+	})
+
+	t.Run("Invalid policy", func(t *testing.T) {
+		diagnostics := re.ValidatePolicy(models.Policy{
+			Name:       "InvalidPolicy",
+			Expression: "input.age >= ",
+			Rules: []models.Rule{
+				{Name: "Rule1", Expression: "Result(10, false)"},
+				{Name: "Rule2", Expression: "Result(10,\n  unknown)"},
+				{Name: "Rule1", Expression: "Result(1, false)"},
+			},
+			Thresholds: []models.Threshold{
+				{ID: "low", Value: 0},
+				{ID: "low", Value: 10},
+			},
+		})
+		assert.True(t, HasErrors(diagnostics))
+
+		byField := make(map[string][]Diagnostic)
+		for _, d := range diagnostics {
+			byField[d.Rule+"/"+d.Field] = append(byField[d.Rule+"/"+d.Field], d)
+		}
+
+		assert.Len(t, byField["/id"], 1)
+		assert.NotEmpty(t, byField["/expression"])
+		assert.Equal(t, SeverityError, byField["/expression"][0].Severity)
+
+		ruleDiagnostics := byField["Rule2/expression"]
+		assert.Len(t, ruleDiagnostics, 1)
+		assert.Equal(t, 2, ruleDiagnostics[0].Line)
+		assert.Equal(t, 3, ruleDiagnostics[0].Column)
+		assert.Contains(t, ruleDiagnostics[0].Message, "unknown")
+
+		assert.Len(t, byField["Rule1/name"], 1)
+		assert.Equal(t, SeverityError, byField["Rule1/name"][0].Severity)
+		assert.Len(t, byField["/thresholds"], 1)
+	})
+
+	t.Run("Wrong output types", func(t *testing.T) {
+		diagnostics := re.ValidatePolicy(models.Policy{
+			ID:         "policy1",
+			Expression: "input.enabled",
+			Rules: []models.Rule{
+				{Name: "Rule1", Expression: "input.age > 18"},
+				{Name: "Rule2", Expression: "\n  'high'"},
+			},
+		})
+		assert.Len(t, diagnostics, 1)
+		assert.Equal(t, "Rule2", diagnostics[0].Rule)
+		assert.Equal(t, 2, diagnostics[0].Line)
+		assert.Equal(t, 3, diagnostics[0].Column)
+		assert.Contains(t, diagnostics[0].Message, "expression returns string")
+		assert.Equal(t, SeverityError, diagnostics[0].Severity)
+
+		diagnostics = re.ValidatePolicy(models.Policy{
+			ID:         "policy1",
+			Expression: "input.age + 1",
+		})
+		assert.Len(t, diagnostics, 1)
+		assert.Equal(t, "expression", diagnostics[0].Field)
+		assert.Equal(t, 1, diagnostics[0].Column)
+		assert.Contains(t, diagnostics[0].Message, "expected bool")
+	})
+
+	t.Run("Invalid constant arguments", func(t *testing.T) {
+		diagnostics := re.ValidatePolicy(models.Policy{
+			ID: "policy1",
+			Rules: []models.Rule{
+				{Name: "Internal", Expression: "ip(input.ip).inRanges(['10.0.0.0/8', '10.0.0.0/33'])"},
+			},
+		})
+		assert.Len(t, diagnostics, 1)
+		assert.Equal(t, "Internal", diagnostics[0].Rule)
+		assert.Contains(t, diagnostics[0].Message, "10.0.0.0/33")
+	})
+
+	t.Run("Unknown outcome mode", func(t *testing.T) {
+		policy := models.Policy{ID: "policy1", OutcomeMode: "dmn"}
+		diagnostics := re.ValidatePolicy(policy)
+		assert.Len(t, diagnostics, 1)
+		assert.Equal(t, "outcome_mode", diagnostics[0].Field)
+		assert.Error(t, re.AddPolicy(policy))
+	})
+
+	t.Run("Negative maximum number of reasons", func(t *testing.T) {
+		policy := models.Policy{ID: "policy1", MaxReasons: -1}
+		diagnostics := re.ValidatePolicy(policy)
+		assert.Len(t, diagnostics, 1)
+		assert.Equal(t, "max_reasons", diagnostics[0].Field)
+		assert.Error(t, re.AddPolicy(policy))
+	})
+
+	t.Run("Invalid when expression", func(t *testing.T) {
+		policy := models.Policy{ID: "policy1", Rules: []models.Rule{{Name: "Rule1", Expression: "10", When: "input.country"}}}
+		assert.Empty(t, re.ValidatePolicy(policy))
+
+		policy.Rules[0].When = "Result(1, false)"
+		diagnostics := re.ValidatePolicy(policy)
+		assert.Len(t, diagnostics, 1)
+		assert.Equal(t, "Rule1", diagnostics[0].Rule)
+		assert.Equal(t, "when", diagnostics[0].Field)
+		assert.Error(t, re.AddPolicy(policy))
+	})
+
+	t.Run("Duplicates", func(t *testing.T) {
+		for _, policy := range []models.Policy{
+			{ID: "policy1", Rules: []models.Rule{{Name: "Rule1", Expression: "1"}, {Name: "Rule1", Expression: "2"}}},
+			{ID: "policy1", Thresholds: []models.Threshold{{ID: "low", Value: 0}, {ID: "low", Value: 10}}},
+			{ID: "policy1", Rules: []models.Rule{{Name: "Group", Group: &models.RuleGroup{
+				Rules:      []models.Rule{{Name: "Rule1", Expression: "1"}, {Name: "Rule1", Expression: "2"}},
+				Thresholds: []models.Threshold{{ID: "low", Value: 0}},
+			}}}},
+			{ID: "policy1", Rules: []models.Rule{{Name: "Group", Group: &models.RuleGroup{
+				Rules:      []models.Rule{{Name: "Rule1", Expression: "1"}},
+				Thresholds: []models.Threshold{{ID: "low", Value: 0}, {ID: "low", Value: 10}},
+			}}}},
+		} {
+			diagnostics := re.ValidatePolicy(policy)
+			assert.Len(t, diagnostics, 1)
+			assert.True(t, HasErrors(diagnostics))
+			err := re.AddPolicy(policy)
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), diagnostics[0].Message)
+			}
+		}
+	})
+
+	t.Run("Compile errors are the first diagnostic", func(t *testing.T) {
+		policy := models.Policy{
+			ID: "policy1",
+			Rules: []models.Rule{
+				{Name: "Rule1", Expression: "Result(10,\n  unknown)"},
+				{Name: "Rule2", Expression: "1", When: "input.country"},
+			},
+			MaxReasons: -1,
+		}
+		diagnostics := re.ValidatePolicy(policy)
+		assert.Len(t, diagnostics, 2)
+		assert.EqualError(t, re.AddPolicy(policy), "invalid expression of rule Rule1 at 2:3: "+diagnostics[0].Message)
+	})
+
+	t.Run("Invalid rule reason", func(t *testing.T) {
+		policy := models.Policy{ID: "policy1", Rules: []models.Rule{{Name: "Rule1", Expression: "Result(1, false, 42)"}}}
+		diagnostics := re.ValidatePolicy(policy)
+		assert.Len(t, diagnostics, 1)
+		assert.Equal(t, "expression", diagnostics[0].Field)
+	})
+}
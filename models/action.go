@@ -0,0 +1,157 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+)
+
+// ActionType is what an action of a threshold does when the threshold is
+// selected.
+type ActionType string
+
+const (
+	// ActionPublish publishes a message to Subject. The message is the JSON
+	// encoding of the value of Expression, or of the decision of the policy
+	// if the action has no expression.
+	ActionPublish ActionType = "publish"
+	// ActionSetAttribute sets the output attribute Name to Value.
+	ActionSetAttribute ActionType = "set_attribute"
+	// ActionAddTag adds the tag Name to the output.
+	ActionAddTag ActionType = "add_tag"
+	// ActionObligation emits the obligation Name with Parameters, that the
+	// consumers of the output must fulfill.
+	ActionObligation ActionType = "obligation"
+)
+
+// Action is an action executed when its threshold is selected. The expression
+// of the publish actions is evaluated with the variables of the threshold
+// conditions: input, score and rules.
+type Action struct {
+	Type            ActionType
+	Subject         string
+	Name            string
+	Value           string
+	Parameters      map[string]string
+	Expression      string
+	CompiledProgram cel.Program
+}
+
+// Check checks that the action has the fields its type requires, and none of
+// the fields of the other types.
+func (a *Action) Check() error {
+	switch a.Type {
+	case ActionPublish:
+		if a.Subject == "" || strings.ContainsAny(a.Subject, " \t\r\n*>") {
+			return fmt.Errorf("publish action requires a subject without spaces and wildcards")
+		}
+		if a.Name != "" || a.Value != "" || len(a.Parameters) > 0 {
+			return fmt.Errorf("publish action takes only a subject and an expression")
+		}
+		return nil
+	case ActionSetAttribute, ActionAddTag, ActionObligation:
+	default:
+		return fmt.Errorf("unknown action type %q", a.Type)
+	}
+
+	if a.Name == "" {
+		return fmt.Errorf("%s action requires a name", a.Type)
+	}
+	if a.Subject != "" || a.Expression != "" {
+		return fmt.Errorf("%s action does not take a subject or an expression", a.Type)
+	}
+	if a.Value != "" && a.Type != ActionSetAttribute {
+		return fmt.Errorf("%s action does not take a value", a.Type)
+	}
+	if len(a.Parameters) > 0 && a.Type != ActionObligation {
+		return fmt.Errorf("%s action does not take parameters", a.Type)
+	}
+	return nil
+}
+
+// Obligation is an obligation emitted by an action, that the consumers of
+// the output of the policy must fulfill.
+type Obligation struct {
+	Name       string
+	Parameters map[string]string
+}
+
+// Message is a message to publish, produced by a publish action.
+type Message struct {
+	Subject string
+	Data    []byte
+}
+
+// ActionResults are the effects of the actions of the selected threshold.
+type ActionResults struct {
+	Attributes  map[string]string
+	Tags        []string
+	Obligations []Obligation
+	Messages    []Message
+}
+
+// runActions executes the actions of the threshold, in order. A later
+// set_attribute action overrides an earlier one with the same name, and a
+// tag is added only once.
+func (p *Policy) runActions(threshold string, score float64, vars map[string]interface{}) (ActionResults, error) {
+	var results ActionResults
+	i := p.thresholdIndex(threshold)
+	if threshold == "" || i < 0 {
+		return results, nil
+	}
+
+	for _, action := range p.Thresholds[i].Actions {
+		switch action.Type {
+		case ActionPublish:
+			data, err := p.publishData(&action, threshold, score, vars)
+			if err != nil {
+				return ActionResults{}, fmt.Errorf("error executing action of threshold %s: %v", threshold, err)
+			}
+			results.Messages = append(results.Messages, Message{Subject: action.Subject, Data: data})
+		case ActionSetAttribute:
+			if results.Attributes == nil {
+				results.Attributes = make(map[string]string)
+			}
+			results.Attributes[action.Name] = action.Value
+		case ActionAddTag:
+			if !slices.Contains(results.Tags, action.Name) {
+				results.Tags = append(results.Tags, action.Name)
+			}
+		case ActionObligation:
+			results.Obligations = append(results.Obligations, Obligation{
+				Name:       action.Name,
+				Parameters: action.Parameters,
+			})
+		default:
+			return ActionResults{}, fmt.Errorf("unknown action type %q", action.Type)
+		}
+	}
+	return results, nil
+}
+
+// publishData returns the payload of a publish action.
+func (p *Policy) publishData(action *Action, threshold string, score float64, vars map[string]interface{}) ([]byte, error) {
+	if action.Expression == "" {
+		return json.Marshal(map[string]interface{}{
+			"policy_id": p.ID,
+			"threshold": threshold,
+			"score":     score,
+		})
+	}
+	if action.CompiledProgram == nil {
+		return nil, fmt.Errorf("publish expression is not compiled")
+	}
+
+	out, _, err := action.CompiledProgram.Eval(vars)
+	if err != nil {
+		return nil, fmt.Errorf("error evaluating publish expression: %v", err)
+	}
+	value, err := nativeValue(out)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/sandrolain/rules/cel"
+	"github.com/sandrolain/rules/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAction_Check(t *testing.T) {
+	tests := []struct {
+		name        string
+		action      Action
+		expectError bool
+	}{
+		{"Publish", Action{Type: ActionPublish, Subject: "payments.blocked", Expression: "{'id': input.id}"}, false},
+		{"Publish without subject", Action{Type: ActionPublish}, true},
+		{"Publish to a wildcard", Action{Type: ActionPublish, Subject: "payments.>"}, true},
+		{"Publish with a name", Action{Type: ActionPublish, Subject: "payments", Name: "blocked"}, true},
+		{"Set attribute", Action{Type: ActionSetAttribute, Name: "review_queue", Value: "high"}, false},
+		{"Add tag", Action{Type: ActionAddTag, Name: "fraud"}, false},
+		{"Add tag with a value", Action{Type: ActionAddTag, Name: "fraud", Value: "yes"}, true},
+		{"Obligation", Action{Type: ActionObligation, Name: "notify", Parameters: map[string]string{"channel": "email"}}, false},
+		{"Obligation with an expression", Action{Type: ActionObligation, Name: "notify", Expression: "true"}, true},
+		{"Missing name", Action{Type: ActionSetAttribute, Value: "high"}, true},
+		{"Unknown type", Action{Type: "delete", Name: "all"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.action.Check()
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPolicy_Actions(t *testing.T) {
+	env, err := cel.CreateRuleEnv()
+	assert.NoError(t, err)
+	policyEnv, err := cel.CreatePolicyEnv()
+	assert.NoError(t, err)
+	decisionEnv, err := cel.NewDecisionEnv(policyEnv)
+	assert.NoError(t, err)
+
+	rules := []Rule{{Name: "Amount", Expression: "input.amount"}}
+	assert.NoError(t, rules[0].BuildProgram(env))
+
+	publish := Action{Type: ActionPublish, Subject: "payments.blocked", Expression: "{'id': input.id, 'score': score, 'amount': decimal('10.10') * decimal(3)}"}
+	publish.CompiledProgram, err = utils.BuildExpression(decisionEnv, publish.Expression, "block")
+	assert.NoError(t, err)
+
+	policy := Policy{
+		ID:    "payments",
+		Rules: rules,
+		Thresholds: []Threshold{
+			{ID: "allow", Value: 0},
+			{
+				ID:    "block",
+				Value: 100,
+				Actions: []Action{
+					publish,
+					{Type: ActionPublish, Subject: "payments.audit"},
+					{Type: ActionSetAttribute, Name: "review_queue", Value: "low"},
+					{Type: ActionSetAttribute, Name: "review_queue", Value: "high"},
+					{Type: ActionAddTag, Name: "fraud"},
+					{Type: ActionAddTag, Name: "fraud"},
+					{Type: ActionObligation, Name: "notify", Parameters: map[string]string{"channel": "email"}},
+				},
+			},
+		},
+	}
+
+	result, err := policy.Execute(MapInput(map[string]interface{}{"id": "p1", "amount": 50.0}))
+	assert.NoError(t, err)
+	assert.Equal(t, "allow", result.Threshold)
+	assert.Equal(t, ActionResults{}, result.Actions)
+
+	result, err = policy.Execute(MapInput(map[string]interface{}{"id": "p2", "amount": 150.0}))
+	assert.NoError(t, err)
+	assert.Equal(t, "block", result.Threshold)
+	assert.Equal(t, map[string]string{"review_queue": "high"}, result.Actions.Attributes)
+	assert.Equal(t, []string{"fraud"}, result.Actions.Tags)
+	assert.Equal(t, []Obligation{{Name: "notify", Parameters: map[string]string{"channel": "email"}}}, result.Actions.Obligations)
+	if assert.Len(t, result.Actions.Messages, 2) {
+		assert.Equal(t, "payments.blocked", result.Actions.Messages[0].Subject)
+		assert.JSONEq(t, `{"id": "p2", "score": 150, "amount": 30.3}`, string(result.Actions.Messages[0].Data))
+		assert.Equal(t, "payments.audit", result.Actions.Messages[1].Subject)
+		assert.JSONEq(t, `{"policy_id": "payments", "threshold": "block", "score": 150}`, string(result.Actions.Messages[1].Data))
+	}
+}
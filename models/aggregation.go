@@ -0,0 +1,171 @@
+package models
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/google/cel-go/cel"
+)
+
+// AggregationMode is how the scores of the executed rules of a policy are
+// combined into the score of the policy.
+type AggregationMode string
+
+const (
+	// AggregationSum adds the scores. It is the mode of the policies without
+	// one.
+	AggregationSum AggregationMode = "sum"
+	// AggregationMax takes the highest score, so that the worst signal wins.
+	AggregationMax AggregationMode = "max"
+	// AggregationMin takes the lowest score.
+	AggregationMin AggregationMode = "min"
+	// AggregationAverage takes the mean of the scores.
+	AggregationAverage AggregationMode = "average"
+	// AggregationWeightedAverage divides the sum of the scores, which are
+	// already multiplied by the weights of their rules, by the sum of the
+	// weights. The weight of a rule without one is 1.
+	AggregationWeightedAverage AggregationMode = "weighted_average"
+	// AggregationProduct multiplies the scores.
+	AggregationProduct AggregationMode = "product"
+	// AggregationCustom evaluates the expression of the aggregation.
+	AggregationCustom AggregationMode = "custom"
+)
+
+// Aggregation is the aggregation of the rule scores of a policy. The
+// expression of the custom mode is evaluated with input and results, the list
+// of the results of the executed rules, each a map with the name, score,
+// weight, passed and stop keys; it must return an int, a double or a
+// decimal.
+type Aggregation struct {
+	Mode            AggregationMode
+	Expression      string
+	CompiledProgram cel.Program
+}
+
+// Check checks the mode of the aggregation and that only the custom mode has
+// an expression.
+func (a Aggregation) Check() error {
+	switch a.Mode {
+	case "", AggregationSum, AggregationMax, AggregationMin, AggregationAverage,
+		AggregationWeightedAverage, AggregationProduct:
+		if a.Expression != "" {
+			return fmt.Errorf("aggregation mode %s does not take an expression", a.mode())
+		}
+	case AggregationCustom:
+		if a.Expression == "" {
+			return fmt.Errorf("custom aggregation requires an expression")
+		}
+	default:
+		return fmt.Errorf("unknown aggregation mode %q", a.Mode)
+	}
+	return nil
+}
+
+func (a Aggregation) mode() AggregationMode {
+	if a.Mode == "" {
+		return AggregationSum
+	}
+	return a.Mode
+}
+
+// aggregate combines the scores of the executed rules. The score of a policy
+// with no executed rules is 0, whatever its mode.
+func (p *Policy) aggregate(activation map[string]interface{}, results []RuleResult) (float64, error) {
+	return aggregate(p.Aggregation, p.Rules, activation, results)
+}
+
+// aggregate combines the scores of the executed rules with the aggregation a,
+// as for the rules of a policy.
+func aggregate(a Aggregation, rules []Rule, activation map[string]interface{}, results []RuleResult) (float64, error) {
+	var scores, weights []float64
+	for i, result := range results {
+		if !result.Executed {
+			continue
+		}
+		scores = append(scores, result.Score)
+		weights = append(weights, rules[i].weight())
+	}
+	if len(scores) == 0 {
+		return 0, nil
+	}
+
+	switch a.mode() {
+	case AggregationSum:
+		return sum(scores), nil
+	case AggregationMax:
+		best := scores[0]
+		for _, s := range scores[1:] {
+			best = max(best, s)
+		}
+		return best, nil
+	case AggregationMin:
+		best := scores[0]
+		for _, s := range scores[1:] {
+			best = min(best, s)
+		}
+		return best, nil
+	case AggregationAverage:
+		return sum(scores) / float64(len(scores)), nil
+	case AggregationWeightedAverage:
+		total := sum(weights)
+		if total == 0 {
+			return 0, fmt.Errorf("the weights of the executed rules sum to 0")
+		}
+		return sum(scores) / total, nil
+	case AggregationProduct:
+		product := 1.0
+		for _, s := range scores {
+			product *= s
+		}
+		return product, nil
+	case AggregationCustom:
+		return a.aggregateCustom(rules, activation, results)
+	}
+	return 0, fmt.Errorf("unknown aggregation mode %q", a.Mode)
+}
+
+func (a Aggregation) aggregateCustom(rules []Rule, activation map[string]interface{}, results []RuleResult) (float64, error) {
+	if a.CompiledProgram == nil {
+		return 0, fmt.Errorf("aggregation expression is not compiled")
+	}
+
+	executed := make([]interface{}, 0, len(results))
+	for i, result := range results {
+		if !result.Executed {
+			continue
+		}
+		executed = append(executed, map[string]interface{}{
+			"name":   rules[i].Name,
+			"score":  result.Score,
+			"weight": rules[i].weight(),
+			"passed": result.Passed,
+			"stop":   result.Stop,
+		})
+	}
+
+	out, _, err := a.CompiledProgram.Eval(map[string]interface{}{
+		"input":   activation["input"],
+		"results": executed,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error evaluating aggregation expression: %v", err)
+	}
+	switch value := out.Value().(type) {
+	case int64:
+		return float64(value), nil
+	case float64:
+		return value, nil
+	case *big.Rat:
+		score, _ := value.Float64()
+		return score, nil
+	}
+	return 0, fmt.Errorf("aggregation expression returned %s instead of a number", out.Type().TypeName())
+}
+
+func sum(values []float64) float64 {
+	var total float64
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
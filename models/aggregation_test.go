@@ -0,0 +1,101 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/sandrolain/rules/cel"
+	"github.com/sandrolain/rules/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy_Aggregation(t *testing.T) {
+	ruleEnv, err := cel.CreateRuleEnv()
+	assert.NoError(t, err)
+	policyEnv, err := cel.CreatePolicyEnv()
+	assert.NoError(t, err)
+	aggregationEnv, err := cel.NewAggregationEnv(policyEnv)
+	assert.NoError(t, err)
+
+	rules := []Rule{
+		{Name: "Velocity", Expression: "input.velocity"},
+		{Name: "Country", Expression: "0.2", Weight: ptr(3.0)},
+		{Name: "Device", Expression: "0.8"},
+		{Name: "Disabled", Expression: "100.0", Disabled: true},
+	}
+	for i := range rules {
+		assert.NoError(t, rules[i].BuildProgram(ruleEnv))
+	}
+	input := map[string]interface{}{"velocity": 0.5}
+
+	tests := []struct {
+		name          string
+		aggregation   Aggregation
+		expectedScore float64
+		expectError   bool
+	}{
+		{name: "Default", aggregation: Aggregation{}, expectedScore: 1.9},
+		{name: "Sum", aggregation: Aggregation{Mode: AggregationSum}, expectedScore: 1.9},
+		{name: "Max", aggregation: Aggregation{Mode: AggregationMax}, expectedScore: 0.8},
+		{name: "Min", aggregation: Aggregation{Mode: AggregationMin}, expectedScore: 0.5},
+		{name: "Average", aggregation: Aggregation{Mode: AggregationAverage}, expectedScore: 1.9 / 3},
+		{name: "Weighted average", aggregation: Aggregation{Mode: AggregationWeightedAverage}, expectedScore: 1.9 / 5},
+		{name: "Product", aggregation: Aggregation{Mode: AggregationProduct}, expectedScore: 0.5 * 0.6 * 0.8},
+		{
+			name:          "Custom",
+			aggregation:   Aggregation{Mode: AggregationCustom, Expression: "results.filter(r, r.name != 'Country').map(r, r.score).max() + input.velocity"},
+			expectedScore: 1.3,
+		},
+		{
+			name:          "Custom count",
+			aggregation:   Aggregation{Mode: AggregationCustom, Expression: "size(results)"},
+			expectedScore: 3,
+		},
+		{
+			name:        "Custom error",
+			aggregation: Aggregation{Mode: AggregationCustom, Expression: "results[5].score"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := Policy{
+				ID:          "policy",
+				Rules:       rules,
+				Aggregation: tt.aggregation,
+				Thresholds:  []Threshold{{ID: "high", Value: 1.5}},
+			}
+			assert.NoError(t, policy.Aggregation.Check())
+			if policy.Aggregation.Expression != "" {
+				program, err := utils.BuildExpression(aggregationEnv, policy.Aggregation.Expression, "aggregation")
+				assert.NoError(t, err)
+				policy.Aggregation.CompiledProgram = program
+			}
+
+			result, err := policy.Execute(MapInput(input))
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.expectedScore, result.Score, 1e-9)
+			assert.Equal(t, tt.expectedScore >= 1.5, result.Threshold == "high")
+			assert.Len(t, result.Rules, 4)
+			assert.InDelta(t, 0.6, result.Rules[1].Score, 1e-9)
+			assert.False(t, result.Rules[3].Executed)
+		})
+	}
+
+	t.Run("No executed rules", func(t *testing.T) {
+		policy := Policy{ID: "policy", Rules: rules[3:], Aggregation: Aggregation{Mode: AggregationProduct}}
+		result, err := policy.Execute(MapInput(input))
+		assert.NoError(t, err)
+		assert.Equal(t, 0.0, result.Score)
+	})
+
+	t.Run("Check", func(t *testing.T) {
+		assert.Error(t, Aggregation{Mode: "median"}.Check())
+		assert.Error(t, Aggregation{Mode: AggregationCustom}.Check())
+		assert.Error(t, Aggregation{Mode: AggregationMax, Expression: "1"}.Check())
+	})
+}
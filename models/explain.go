@@ -0,0 +1,239 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/parser"
+)
+
+// Explainer evaluates an expression recording the values of its
+// sub-expressions. Its program, which tracks the evaluation state and is
+// slower, is created the first time it is needed.
+type Explainer struct {
+	env  *cel.Env
+	ast  *cel.Ast
+	once sync.Once
+	// program and exprs are set by init
+	program cel.Program
+	exprs   []subExpression
+	err     error
+}
+
+type subExpression struct {
+	id   int64
+	text string
+}
+
+// NewExplainer returns an explainer of the expression checked in env.
+func NewExplainer(env *cel.Env, checked *cel.Ast) *Explainer {
+	return &Explainer{env: env, ast: checked}
+}
+
+func (e *Explainer) init() {
+	e.program, e.err = e.env.Program(e.ast, cel.EvalOptions(cel.OptTrackState))
+	if e.err != nil {
+		return
+	}
+
+	// The sub-expressions are listed from the outermost, without the literals
+	// and the internals of the comprehensions, whose variables change at every
+	// step
+	native := e.ast.NativeRep()
+	var walk func(expr ast.NavigableExpr, root bool)
+	walk = func(expr ast.NavigableExpr, root bool) {
+		if expr.Kind() == ast.LiteralKind {
+			return
+		}
+		if !root {
+			text, err := parser.Unparse(expr, native.SourceInfo())
+			if err == nil && !strings.HasPrefix(text, "@") {
+				e.exprs = append(e.exprs, subExpression{id: expr.ID(), text: text})
+			}
+		}
+		if expr.Kind() == ast.ComprehensionKind {
+			return
+		}
+		for _, child := range expr.Children() {
+			walk(child, false)
+		}
+	}
+	walk(ast.NavigateAST(native), true)
+}
+
+// Explain evaluates the expression and returns its trace.
+func (e *Explainer) Explain(vars map[string]interface{}) *ExpressionTrace {
+	_, trace, _ := e.evaluate(vars)
+	return trace
+}
+
+// evaluate evaluates the expression and returns its value and its trace,
+// or the error of the evaluation, which is also reported in the trace.
+func (e *Explainer) evaluate(vars map[string]interface{}) (ref.Val, *ExpressionTrace, error) {
+	e.once.Do(e.init)
+	if e.err != nil {
+		return nil, &ExpressionTrace{Error: e.err.Error()}, e.err
+	}
+
+	out, details, err := e.program.Eval(vars)
+	trace := &ExpressionTrace{}
+	if err != nil {
+		trace.Error = err.Error()
+	} else {
+		trace.Value = traceValue(out)
+	}
+	if details == nil {
+		return out, trace, err
+	}
+	for _, expr := range e.exprs {
+		if val, ok := details.State().Value(expr.id); ok {
+			trace.SubExpressions = append(trace.SubExpressions, SubExpression{
+				Expression: expr.text,
+				Value:      traceValue(val),
+			})
+		}
+	}
+	return out, trace, err
+}
+
+// traceValue converts a value for a trace. The values that cannot be encoded
+// in JSON, like errors and types, are reported as strings.
+func traceValue(val ref.Val) interface{} {
+	if value, err := nativeValue(val); err == nil {
+		return value
+	}
+	return fmt.Sprint(val.Value())
+}
+
+// Trace explains the evaluation of a policy: the value of its expression,
+// the evaluation of each rule and which thresholds the score reached.
+// Executed is false if the expression was false and the rules were not
+// evaluated.
+type Trace struct {
+	Gate       *ExpressionTrace
+	Executed   bool
+	Rules      []RuleTrace
+	Thresholds []ThresholdTrace
+}
+
+// ExpressionTrace is the value of an expression, or the error of its
+// evaluation, and the values of its sub-expressions. The sub-expressions are
+// listed only if the expression was compiled by the engine.
+type ExpressionTrace struct {
+	Value          interface{}
+	Error          string
+	SubExpressions []SubExpression
+}
+
+type SubExpression struct {
+	Expression string
+	Value      interface{}
+}
+
+// RuleStatus tells whether a rule was evaluated.
+type RuleStatus string
+
+const (
+	RuleExecuted RuleStatus = "executed"
+	RuleDisabled RuleStatus = "disabled"
+	// RuleSkipped is the status of the rules after the one that stopped the
+	// evaluation.
+	RuleSkipped RuleStatus = "skipped"
+	// RuleNotApplicable is the status of the rules whose when expression was
+	// false.
+	RuleNotApplicable RuleStatus = "not_applicable"
+)
+
+// RuleTrace explains the evaluation of a rule. Duration is the time of the
+// evaluation of its expression, measured without tracking the values of the
+// sub-expressions, and StoppedBy is the rule that stopped the evaluation
+// before a skipped rule.
+type RuleTrace struct {
+	Name       string
+	Status     RuleStatus
+	StoppedBy  string
+	Duration   time.Duration
+	Expression *ExpressionTrace
+	// Rules are the traces of the rules of a group
+	Rules []RuleTrace
+}
+
+// ThresholdTrace tells whether a threshold was reached by the score, or by
+// its condition if it has one.
+type ThresholdTrace struct {
+	ID        string
+	Disabled  bool
+	Position  float64
+	Reached   bool
+	Condition *bool
+}
+
+// ExecuteWithTrace evaluates the policy like Execute, and also explains the
+// evaluation in the Trace of the result. Unlike Execute, it evaluates the
+// policy expression first: if it is false the rules are not evaluated and
+// the result has only the trace.
+func (p *Policy) ExecuteWithTrace(input Input) (PolicyResult, error) {
+	activation, err := p.activation(input)
+	if err != nil {
+		return PolicyResult{}, err
+	}
+
+	trace := &Trace{Executed: true}
+	if p.CompiledProgram != nil {
+		// The policy expression is evaluated once: the value in the trace is
+		// the one that decides the execution
+		var execute bool
+		if p.Explainer != nil {
+			var out ref.Val
+			out, trace.Gate, err = p.Explainer.evaluate(activation)
+			if err == nil {
+				execute, err = gateValue(out)
+			}
+		} else {
+			execute, err = p.shouldExecute(activation)
+			trace.Gate = &ExpressionTrace{Value: execute}
+		}
+		if err != nil {
+			return PolicyResult{}, err
+		}
+		if !execute {
+			trace.Executed = false
+			return PolicyResult{Trace: trace}, nil
+		}
+	}
+
+	result, err := p.execute(activation, trace)
+	if err != nil {
+		return PolicyResult{}, err
+	}
+	result.Trace = trace
+	return result, nil
+}
+
+// traceThresholds tells which of the thresholds were reached.
+func (p *Policy) traceThresholds(score float64, vars map[string]interface{}) ([]ThresholdTrace, error) {
+	traces := make([]ThresholdTrace, len(p.Thresholds))
+	for i := range p.Thresholds {
+		t := &p.Thresholds[i]
+		traces[i] = ThresholdTrace{
+			ID:       t.ID,
+			Disabled: t.Disabled,
+			Position: t.Position(),
+			Reached:  t.reachedBy(score, p.ThresholdDirection),
+		}
+		if t.Condition != "" {
+			reached, err := t.evaluateCondition(vars)
+			if err != nil {
+				return nil, err
+			}
+			traces[i].Reached = reached
+			traces[i].Condition = &reached
+		}
+	}
+	return traces, nil
+}
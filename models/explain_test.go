@@ -0,0 +1,99 @@
+package models
+
+import (
+	"testing"
+
+	gocel "github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/sandrolain/rules/cel"
+	"github.com/sandrolain/rules/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplainer_Explain(t *testing.T) {
+	env, err := cel.CreateRuleEnv()
+	assert.NoError(t, err)
+
+	ast, _, err := utils.CompileExpression(env, "input.amount > 100 && [1, 2].exists(x, x == input.count)", "test")
+	assert.NoError(t, err)
+	explainer := NewExplainer(env, ast)
+
+	trace := explainer.Explain(map[string]interface{}{"input": map[string]interface{}{"amount": 150, "count": 2}})
+	assert.Equal(t, true, trace.Value)
+	assert.Empty(t, trace.Error)
+	assert.Equal(t, []SubExpression{
+		{Expression: "input.amount > 100", Value: true},
+		{Expression: "input.amount", Value: int64(150)},
+		{Expression: "[1, 2].exists(x, x == input.count)", Value: true},
+	}, trace.SubExpressions)
+
+	trace = explainer.Explain(map[string]interface{}{"input": map[string]interface{}{}})
+	assert.Nil(t, trace.Value)
+	assert.NotEmpty(t, trace.Error)
+}
+
+func TestPolicy_ExecuteWithTrace(t *testing.T) {
+	env, err := cel.CreateRuleEnv()
+	assert.NoError(t, err)
+
+	rules := []Rule{
+		{Name: "Blocked", Expression: "Result(100, input.blocked)"},
+		{Name: "Disabled", Expression: "Result(1, false)", Disabled: true},
+		{Name: "Amount", Expression: "Result(input.amount / 10, false)"},
+	}
+	for i := range rules {
+		assert.NoError(t, rules[i].BuildProgram(env))
+	}
+	policy := Policy{
+		ID:    "payments",
+		Rules: rules,
+		Thresholds: []Threshold{
+			{ID: "allow", Value: 0},
+			{ID: "block", Value: 100},
+			{ID: "review", Value: 50, Disabled: true},
+		},
+	}
+
+	result, err := policy.ExecuteWithTrace(MapInput(map[string]interface{}{"blocked": true, "amount": 20}))
+	assert.NoError(t, err)
+	assert.Equal(t, "block", result.Threshold)
+	if assert.NotNil(t, result.Trace) {
+		assert.True(t, result.Trace.Executed)
+		assert.Nil(t, result.Trace.Gate)
+		assert.Len(t, result.Trace.Rules, 3)
+		assert.Equal(t, RuleExecuted, result.Trace.Rules[0].Status)
+		assert.Equal(t, RuleTrace{Name: "Disabled", Status: RuleDisabled}, result.Trace.Rules[1])
+		assert.Equal(t, RuleTrace{Name: "Amount", Status: RuleSkipped, StoppedBy: "Blocked"}, result.Trace.Rules[2])
+		assert.Equal(t, []ThresholdTrace{
+			{ID: "allow", Position: 0, Reached: true},
+			{ID: "block", Position: 100, Reached: true},
+			{ID: "review", Position: 50, Reached: true, Disabled: true},
+		}, result.Trace.Thresholds)
+	}
+
+	result, err = policy.Execute(MapInput(map[string]interface{}{"blocked": true, "amount": 20}))
+	assert.NoError(t, err)
+	assert.Nil(t, result.Trace)
+}
+
+func TestPolicy_ExecuteWithTrace_GateEvaluatedOnce(t *testing.T) {
+	calls := int64(0)
+	env, err := cel.NewPolicyEnv(nil, gocel.Function("calls",
+		gocel.Overload("calls", nil, gocel.IntType, gocel.FunctionBinding(func(...ref.Val) ref.Val {
+			calls++
+			return types.Int(calls)
+		})),
+	))
+	assert.NoError(t, err)
+
+	ast, program, err := utils.CompileExpression(env, "calls() == 1", "gate")
+	assert.NoError(t, err)
+	policy := Policy{ID: "payments", Expression: "calls() == 1", CompiledProgram: program, Explainer: NewExplainer(env, ast)}
+
+	result, err := policy.ExecuteWithTrace(MapInput(nil))
+	assert.NoError(t, err)
+	assert.True(t, result.Trace.Executed)
+	assert.Equal(t, true, result.Trace.Gate.Value)
+	assert.Equal(t, int64(1), calls)
+}
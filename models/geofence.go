@@ -0,0 +1,9 @@
+package models
+
+// Geofence is a named area declared in a policy as a GeoJSON Polygon or
+// MultiPolygon, or a Feature or FeatureCollection of them. The expressions of
+// the policy test points against it with geofence(name).contains(lat, lon).
+type Geofence struct {
+	Name    string
+	GeoJSON string
+}
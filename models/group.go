@@ -0,0 +1,115 @@
+package models
+
+import "fmt"
+
+// RuleGroup is a group of rules evaluated as a single rule of the policy or
+// of the parent group. The guard of the group is the When expression of its
+// rule, and the score fields of the rule apply to the score of the group.
+//
+// The rules of the group are evaluated in order until one of them stops, and
+// their scores are combined by the aggregation of the group. The stop ends
+// only the evaluation of the group, unless PropagateStop is set: then the
+// rules after the group are not evaluated either. The group passes if all
+// its executed rules pass, and it is not applicable if none of them is
+// executed. The thresholds of the group, which cannot have conditions or
+// actions, are selected by the aggregated score of the group in the
+// threshold direction of the policy.
+type RuleGroup struct {
+	Rules         []Rule
+	Aggregation   Aggregation
+	PropagateStop bool
+	Thresholds    []Threshold
+}
+
+// CheckGroup checks that the rule has either an expression or a group, and
+// the aggregation and the thresholds of its group. The rules of the group
+// are not checked.
+func (r *Rule) CheckGroup() error {
+	if r.Group == nil {
+		if r.Expression == "" {
+			return fmt.Errorf("rule %s has no expression", r.Name)
+		}
+		return nil
+	}
+
+	if r.Expression != "" {
+		return fmt.Errorf("rule %s has both an expression and a group", r.Name)
+	}
+	if err := r.Group.Aggregation.Check(); err != nil {
+		return fmt.Errorf("group %s: %v", r.Name, err)
+	}
+	if err := CheckThresholds(r.Group.Thresholds); err != nil {
+		return fmt.Errorf("group %s: %v", r.Name, err)
+	}
+	for _, t := range r.Group.Thresholds {
+		if t.Condition != "" || len(t.Actions) > 0 {
+			return fmt.Errorf("group %s: threshold %s cannot have a condition or actions", r.Name, t.ID)
+		}
+	}
+	return nil
+}
+
+// evaluateGroup evaluates the rules of the group of rule, recording their
+// evaluation in trace if it is not nil. The score fields of the rule are
+// applied to the aggregated score of the group.
+func (p *Policy) evaluateGroup(rule Rule, activation map[string]interface{}, trace *RuleTrace) (RuleResult, error) {
+	var traces *[]RuleTrace
+	if trace != nil {
+		traces = &trace.Rules
+	}
+	g := rule.Group
+	results, stoppedBy, err := p.evaluateRules(g.Rules, activation, false, traces)
+	if err != nil {
+		return RuleResult{}, err
+	}
+
+	// A group none of whose rules was executed does not apply either, so it
+	// does not contribute the score of an empty aggregation
+	passed, executed := true, false
+	for _, result := range results {
+		if result.Executed {
+			passed = passed && result.Passed
+			executed = true
+		}
+	}
+	if !executed {
+		if trace != nil {
+			trace.Status = RuleNotApplicable
+		}
+		return RuleResult{NotApplicable: true, Rules: results}, nil
+	}
+
+	score, err := aggregate(g.Aggregation, g.Rules, activation, results)
+	if err != nil {
+		return RuleResult{}, err
+	}
+	threshold, err := selectThreshold(g.Thresholds, p.ThresholdDirection, score, nil)
+	if err != nil {
+		return RuleResult{}, err
+	}
+
+	return RuleResult{
+		Score:     rule.score(score),
+		Stop:      g.PropagateStop && stoppedBy != "",
+		Passed:    passed,
+		Executed:  true,
+		Threshold: threshold,
+		Rules:     results,
+	}, nil
+}
+
+// cloneRules copies rules and their groups, so that the copy does not share
+// its slices with them.
+func cloneRules(rules []Rule) []Rule {
+	rules = append([]Rule(nil), rules...)
+	for i, rule := range rules {
+		if rule.Group == nil {
+			continue
+		}
+		group := *rule.Group
+		group.Rules = cloneRules(group.Rules)
+		group.Thresholds = append([]Threshold(nil), group.Thresholds...)
+		rules[i].Group = &group
+	}
+	return rules
+}
@@ -0,0 +1,147 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/sandrolain/rules/cel"
+	"github.com/sandrolain/rules/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRule_CheckGroup(t *testing.T) {
+	tests := []struct {
+		name        string
+		rule        Rule
+		expectError bool
+	}{
+		{"Rule", Rule{Name: "Rule", Expression: "1"}, false},
+		{"Rule without expression", Rule{Name: "Rule"}, true},
+		{"Group", Rule{Name: "Group", Group: &RuleGroup{Thresholds: []Threshold{{ID: "low", Value: 0}}}}, false},
+		{"Group with expression", Rule{Name: "Group", Expression: "1", Group: &RuleGroup{}}, true},
+		{"Group with invalid aggregation", Rule{Name: "Group", Group: &RuleGroup{Aggregation: Aggregation{Mode: "median"}}}, true},
+		{"Group with overlapping thresholds", Rule{Name: "Group", Group: &RuleGroup{Thresholds: []Threshold{
+			{ID: "low", Range: &ScoreRange{Min: ptr(0.0), Max: ptr(20.0)}},
+			{ID: "high", Range: &ScoreRange{Min: ptr(10.0)}},
+		}}}, true},
+		{"Group with threshold condition", Rule{Name: "Group", Group: &RuleGroup{Thresholds: []Threshold{{ID: "low", Condition: "true"}}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.CheckGroup()
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPolicy_RuleGroups(t *testing.T) {
+	env, err := cel.CreateRuleEnv()
+	assert.NoError(t, err)
+
+	build := func(rules ...Rule) []Rule {
+		for i := range rules {
+			assert.NoError(t, rules[i].BuildProgram(env))
+		}
+		return rules
+	}
+	identity := Rule{Name: "Identity", Group: &RuleGroup{
+		Rules: build(
+			Rule{Name: "Document", Expression: "Result(input.document ? 0 : 30, false, 'NO_DOCUMENT')"},
+			Rule{Name: "Sanctions", Expression: "Result(input.sanctioned ? 100 : 0, input.sanctioned)"},
+			Rule{Name: "Email", Expression: "10"},
+		),
+		Aggregation: Aggregation{Mode: AggregationMax},
+		Thresholds:  []Threshold{{ID: "clear", Value: 0}, {ID: "suspect", Value: 30}},
+	}}
+	policy := Policy{
+		ID:    "onboarding",
+		Rules: append([]Rule{identity}, build(Rule{Name: "Amount", Expression: "input.amount / 10"})...),
+	}
+
+	result, err := policy.Execute(MapInput(map[string]interface{}{"document": false, "sanctioned": false, "amount": 50}))
+	assert.NoError(t, err)
+	assert.Equal(t, 35.0, result.Score)
+	group := result.Rules[0]
+	assert.True(t, group.Executed)
+	assert.Equal(t, 30.0, group.Score)
+	assert.Equal(t, "suspect", group.Threshold)
+	assert.Len(t, group.Rules, 3)
+	assert.Equal(t, 30.0, group.Rules[0].Score)
+	assert.Equal(t, []Reason{{Code: "NO_DOCUMENT", Rule: "Document", Score: 30}}, result.Reasons)
+
+	t.Run("A stop ends only the group", func(t *testing.T) {
+		result, err := policy.Execute(MapInput(map[string]interface{}{"document": true, "sanctioned": true, "amount": 50}))
+		assert.NoError(t, err)
+		group := result.Rules[0]
+		assert.Equal(t, 100.0, group.Score)
+		assert.False(t, group.Stop)
+		assert.False(t, group.Rules[2].Executed)
+		assert.True(t, result.Rules[1].Executed)
+		assert.Equal(t, 105.0, result.Score)
+	})
+
+	t.Run("Propagated stop", func(t *testing.T) {
+		stopping := policy.Clone()
+		stopping.Rules[0].Group.PropagateStop = true
+		assert.False(t, policy.Rules[0].Group.PropagateStop)
+
+		result, err := stopping.Execute(MapInput(map[string]interface{}{"document": true, "sanctioned": true, "amount": 50}))
+		assert.NoError(t, err)
+		assert.True(t, result.Rules[0].Stop)
+		assert.False(t, result.Rules[1].Executed)
+		assert.Equal(t, 100.0, result.Score)
+	})
+
+	t.Run("Trace", func(t *testing.T) {
+		result, err := policy.ExecuteWithTrace(MapInput(map[string]interface{}{"document": true, "sanctioned": true, "amount": 50}))
+		assert.NoError(t, err)
+		assert.Len(t, result.Trace.Rules, 2)
+		groupTrace := result.Trace.Rules[0]
+		assert.Equal(t, RuleExecuted, groupTrace.Status)
+		assert.Len(t, groupTrace.Rules, 3)
+		assert.Equal(t, RuleTrace{Name: "Email", Status: RuleSkipped, StoppedBy: "Sanctions"}, groupTrace.Rules[2])
+	})
+
+	t.Run("A group without applicable rules is not applicable", func(t *testing.T) {
+		when := func(rule Rule, expression string) Rule {
+			program, err := utils.BuildExpression(env, expression, rule.Name)
+			assert.NoError(t, err)
+			rule.When, rule.CompiledWhen = expression, program
+			return rule
+		}
+		foreign := Rule{Name: "Foreign", Group: &RuleGroup{
+			Rules: build(
+				Rule{Name: "Country", Expression: "30"},
+				Rule{Name: "Currency", Expression: "20"},
+			),
+			Thresholds: []Threshold{{ID: "clear", Value: 0}},
+		}}
+		foreign.Group.Rules[0] = when(foreign.Group.Rules[0], "input.country != 'IT'")
+		foreign.Group.Rules[1] = when(foreign.Group.Rules[1], "input.currency != 'EUR'")
+		policy := Policy{
+			ID:    "payments",
+			Rules: append([]Rule{when(foreign, "input.amount > 0")}, build(Rule{Name: "Amount", Expression: "input.amount / 10"})...),
+		}
+
+		result, err := policy.ExecuteWithTrace(MapInput(map[string]interface{}{"country": "IT", "currency": "EUR", "amount": 50}))
+		assert.NoError(t, err)
+		assert.Equal(t, 5.0, result.Score)
+		assert.Equal(t, RuleResult{
+			NotApplicable: true,
+			Rules:         []RuleResult{{NotApplicable: true}, {NotApplicable: true}},
+		}, result.Rules[0])
+		groupTrace := result.Trace.Rules[0]
+		assert.Equal(t, RuleNotApplicable, groupTrace.Status)
+		assert.Len(t, groupTrace.Rules, 2)
+
+		result, err = policy.Execute(MapInput(map[string]interface{}{"country": "FR", "currency": "EUR", "amount": 50}))
+		assert.NoError(t, err)
+		assert.True(t, result.Rules[0].Executed)
+		assert.Equal(t, "clear", result.Rules[0].Threshold)
+		assert.Equal(t, 35.0, result.Score)
+	})
+}
@@ -0,0 +1,240 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	rcel "github.com/sandrolain/rules/cel"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// Input is the input of an evaluation: either the values decoded from a JSON
+// payload, or a protobuf message decoded from a binary payload.
+type Input struct {
+	Values  map[string]interface{}
+	Message proto.Message
+}
+
+func MapInput(values map[string]interface{}) Input {
+	return Input{Values: values}
+}
+
+func MessageInput(msg proto.Message) Input {
+	return Input{Message: msg}
+}
+
+// DecodeJSON decodes a JSON object payload. Numbers are decoded as float64
+// values, unless exactNumbers is set: then the integers in the int64 range
+// are decoded as int64 values and the other numbers as decimals, so that
+// large integers and amounts keep all their digits.
+func DecodeJSON(data []byte, exactNumbers bool) (Input, error) {
+	var values map[string]interface{}
+	if !exactNumbers {
+		if err := json.Unmarshal(data, &values); err != nil {
+			return Input{}, err
+		}
+		return MapInput(values), nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&values); err != nil {
+		return Input{}, err
+	}
+	if decoder.More() {
+		return Input{}, fmt.Errorf("unexpected data after the JSON object")
+	}
+	if _, err := exactNumber(values); err != nil {
+		return Input{}, err
+	}
+	return MapInput(values), nil
+}
+
+// exactNumber replaces the json.Number values in a decoded value. Maps and
+// lists are updated in place.
+func exactNumber(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := strconv.ParseInt(v.String(), 10, 64); err == nil {
+			return i, nil
+		}
+		return rcel.ParseDecimal(v.String())
+	case map[string]interface{}:
+		for k, elem := range v {
+			value, err := exactNumber(elem)
+			if err != nil {
+				return nil, err
+			}
+			v[k] = value
+		}
+	case []interface{}:
+		for i, elem := range v {
+			value, err := exactNumber(elem)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = value
+		}
+	}
+	return v, nil
+}
+
+var (
+	// The fields of the input that are not declared by the schema are ignored
+	inputUnmarshal = protojson.UnmarshalOptions{DiscardUnknown: true}
+	inputMarshal   = protojson.MarshalOptions{UseProtoNames: true}
+)
+
+// activation returns the variables used to evaluate the expressions of the
+// policy. If the policy has an input type, the input is converted to a
+// message of that type; otherwise it is converted to a map of values.
+//
+// A message of the input type of the policy is used as it is, so policies
+// declaring the type of a bound subject evaluate the decoded message
+// directly.
+func (p *Policy) activation(input Input) (map[string]interface{}, error) {
+	if p.InputType == nil {
+		values, err := input.values()
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"input": values}, nil
+	}
+
+	msg, err := input.message(p.InputType)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"input": msg}, nil
+}
+
+func (in Input) values() (map[string]interface{}, error) {
+	if in.Message == nil {
+		return in.Values, nil
+	}
+	return messageValues(in.Message.ProtoReflect())
+}
+
+// messageValues converts a message to a map of values. Integers keep their
+// type, while enums, well-known types and nested messages are converted as
+// in JSON payloads. Fields without presence are set even if unpopulated, as
+// their value is defined by the message.
+func messageValues(m protoreflect.Message) (map[string]interface{}, error) {
+	fields := m.Descriptor().Fields()
+	values := make(map[string]interface{}, fields.Len())
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if fd.HasPresence() && !m.Has(fd) {
+			continue
+		}
+		value, err := fieldValue(fd, m.Get(fd))
+		if err != nil {
+			return nil, err
+		}
+		values[string(fd.Name())] = value
+	}
+	return values, nil
+}
+
+func fieldValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) (interface{}, error) {
+	switch {
+	case fd.IsList():
+		list := v.List()
+		values := make([]interface{}, list.Len())
+		for i := range values {
+			value, err := singularValue(fd, list.Get(i))
+			if err != nil {
+				return nil, err
+			}
+			values[i] = value
+		}
+		return values, nil
+	case fd.IsMap():
+		values := make(map[string]interface{}, v.Map().Len())
+		var err error
+		v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+			values[k.String()], err = singularValue(fd.MapValue(), mv)
+			return err == nil
+		})
+		return values, err
+	default:
+		return singularValue(fd, v)
+	}
+}
+
+func singularValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) (interface{}, error) {
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name()), nil
+		}
+		return int64(v.Enum()), nil
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		msg := v.Message()
+		if msg.Descriptor().FullName().Parent() != "google.protobuf" {
+			return messageValues(msg)
+		}
+		// Well-known types have their own JSON form
+		data, err := inputMarshal.Marshal(msg.Interface())
+		if err != nil {
+			return nil, fmt.Errorf("error converting input field %s: %v", fd.FullName(), err)
+		}
+		var value interface{}
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, fmt.Errorf("error converting input field %s: %v", fd.FullName(), err)
+		}
+		return value, nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return v.Int(), nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return v.Uint(), nil
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return v.Float(), nil
+	default:
+		return v.Interface(), nil
+	}
+}
+
+func (in Input) message(inputType protoreflect.MessageDescriptor) (proto.Message, error) {
+	if in.Message != nil {
+		desc := in.Message.ProtoReflect().Descriptor()
+		if desc == inputType {
+			return in.Message, nil
+		}
+		if desc.FullName() == inputType.FullName() {
+			// The same type, from another registration of its descriptor set
+			data, err := proto.Marshal(in.Message)
+			if err != nil {
+				return nil, fmt.Errorf("error converting input: %v", err)
+			}
+			msg := dynamicpb.NewMessage(inputType)
+			if err := proto.Unmarshal(data, msg); err != nil {
+				return nil, fmt.Errorf("input does not match the schema %s: %v", inputType.FullName(), err)
+			}
+			return msg, nil
+		}
+	}
+
+	var data []byte
+	var err error
+	if in.Message != nil {
+		data, err = inputMarshal.Marshal(in.Message)
+	} else {
+		data, err = json.Marshal(in.Values)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error converting input: %v", err)
+	}
+	msg := dynamicpb.NewMessage(inputType)
+	if err := inputUnmarshal.Unmarshal(data, msg); err != nil {
+		return nil, fmt.Errorf("input does not match the schema %s: %v", inputType.FullName(), err)
+	}
+	return msg, nil
+}
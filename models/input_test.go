@@ -0,0 +1,56 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/sandrolain/rules/cel"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeJSON(t *testing.T) {
+	data := []byte(`{"amount": 19.99, "count": 3, "id": 12345678901234567890, "items": [{"price": 0.1}]}`)
+
+	t.Run("Float numbers", func(t *testing.T) {
+		input, err := DecodeJSON(data, false)
+		assert.NoError(t, err)
+		assert.Equal(t, 19.99, input.Values["amount"])
+		assert.Equal(t, 3.0, input.Values["count"])
+	})
+
+	t.Run("Exact numbers", func(t *testing.T) {
+		input, err := DecodeJSON(data, true)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), input.Values["count"])
+
+		amount, ok := input.Values["amount"].(cel.Decimal)
+		assert.True(t, ok)
+		assert.Equal(t, "19.99", amount.String())
+
+		id, ok := input.Values["id"].(cel.Decimal)
+		assert.True(t, ok)
+		assert.Equal(t, "12345678901234567890", id.String())
+
+		item := input.Values["items"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "0.1", item["price"].(cel.Decimal).String())
+	})
+
+	t.Run("Exact numbers in rules", func(t *testing.T) {
+		env, err := cel.CreateRuleEnv()
+		assert.NoError(t, err)
+		rule := Rule{Name: "Total", Expression: "input.items[0].price * decimal(3) == decimal('0.3') && input.amount > 19"}
+		assert.NoError(t, rule.BuildProgram(env))
+
+		input, err := DecodeJSON(data, true)
+		assert.NoError(t, err)
+		result, err := rule.Evaluate(input.Values)
+		assert.NoError(t, err)
+		assert.True(t, result.Passed)
+	})
+
+	t.Run("Invalid payloads", func(t *testing.T) {
+		for _, payload := range []string{`{"a": 1`, `{"a": 1} {}`, `[1, 2]`} {
+			_, err := DecodeJSON([]byte(payload), true)
+			assert.Error(t, err, payload)
+		}
+	})
+}
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OutcomeMode is how the outcome of a policy is decided from the results of
+// its rules.
+type OutcomeMode string
+
+const (
+	// OutcomeThreshold takes the highest threshold reached by the score of
+	// the policy. It is the mode of the policies without one.
+	OutcomeThreshold OutcomeMode = "threshold"
+	// OutcomeAllPass is "pass" if every executed rule passed, and "fail"
+	// otherwise.
+	OutcomeAllPass OutcomeMode = "all_pass"
+	// OutcomeAnyPass is "pass" if any executed rule passed, and "fail"
+	// otherwise.
+	OutcomeAnyPass OutcomeMode = "any_pass"
+	// OutcomeFirst takes the outcome of the first rule that passes. The rules
+	// after it are not evaluated.
+	OutcomeFirst OutcomeMode = "first"
+	// OutcomeUnique takes the outcome of the only rule that passes. More than
+	// one passing rule is an error.
+	OutcomeUnique OutcomeMode = "unique"
+	// OutcomePriority takes the outcome of the passing rule with the highest
+	// priority, or of the first of them if their priorities are equal.
+	OutcomePriority OutcomeMode = "priority"
+	// OutcomeCollect takes the outcomes of all the passing rules, in order.
+	OutcomeCollect OutcomeMode = "collect"
+)
+
+const (
+	OutcomePass = "pass"
+	OutcomeFail = "fail"
+)
+
+// CheckOutcomeMode checks that a mode is one of the outcome modes.
+func CheckOutcomeMode(mode OutcomeMode) error {
+	switch mode {
+	case "", OutcomeThreshold, OutcomeAllPass, OutcomeAnyPass, OutcomeFirst,
+		OutcomeUnique, OutcomePriority, OutcomeCollect:
+		return nil
+	}
+	return fmt.Errorf("unknown outcome mode %q", mode)
+}
+
+// outcome decides the outcome of the policy. The hit policy modes leave the
+// outcome empty if no rule passes; the collect mode returns the list of the
+// outcomes instead.
+func (p *Policy) outcome(threshold string, results []RuleResult) (string, []string, error) {
+	switch p.OutcomeMode {
+	case "", OutcomeThreshold:
+		return threshold, nil, nil
+	case OutcomeAllPass, OutcomeAnyPass:
+		anyPassed, allPassed := false, true
+		for _, result := range results {
+			if result.Executed {
+				anyPassed = anyPassed || result.Passed
+				allPassed = allPassed && result.Passed
+			}
+		}
+		if (p.OutcomeMode == OutcomeAllPass && allPassed) || (p.OutcomeMode == OutcomeAnyPass && anyPassed) {
+			return OutcomePass, nil, nil
+		}
+		return OutcomeFail, nil, nil
+	}
+
+	var matched []int
+	for i, result := range results {
+		if result.Executed && result.Passed {
+			matched = append(matched, i)
+		}
+	}
+
+	switch p.OutcomeMode {
+	case OutcomeFirst:
+		if len(matched) == 0 {
+			return "", nil, nil
+		}
+		return p.Rules[matched[0]].outcome(), nil, nil
+	case OutcomeUnique:
+		if len(matched) > 1 {
+			names := make([]string, len(matched))
+			for i, m := range matched {
+				names[i] = p.Rules[m].Name
+			}
+			return "", nil, fmt.Errorf("unique outcome mode: rules %s all passed", strings.Join(names, ", "))
+		}
+		if len(matched) == 0 {
+			return "", nil, nil
+		}
+		return p.Rules[matched[0]].outcome(), nil, nil
+	case OutcomePriority:
+		best := -1
+		for _, m := range matched {
+			if best < 0 || p.Rules[m].Priority > p.Rules[best].Priority {
+				best = m
+			}
+		}
+		if best < 0 {
+			return "", nil, nil
+		}
+		return p.Rules[best].outcome(), nil, nil
+	case OutcomeCollect:
+		outcomes := make([]string, len(matched))
+		for i, m := range matched {
+			outcomes[i] = p.Rules[m].outcome()
+		}
+		return "", outcomes, nil
+	}
+	return "", nil, fmt.Errorf("unknown outcome mode %q", p.OutcomeMode)
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/sandrolain/rules/cel"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy_OutcomeMode(t *testing.T) {
+	env, err := cel.CreateRuleEnv()
+	assert.NoError(t, err)
+
+	rules := []Rule{
+		{Name: "Adult", Expression: "input.age >= 18"},
+		{Name: "Premium", Expression: "input.tier == 'premium'", Outcome: "premium_queue", Priority: 10},
+		{Name: "Priority", Expression: "input.tier != ''", Outcome: "priority_queue", Priority: 20},
+		{Name: "Default", Expression: "true", Outcome: "standard_queue"},
+	}
+	for i := range rules {
+		assert.NoError(t, rules[i].BuildProgram(env))
+	}
+
+	tests := []struct {
+		name             string
+		mode             OutcomeMode
+		input            map[string]interface{}
+		expectedOutcome  string
+		expectedOutcomes []string
+		expectedExecuted int
+		expectError      bool
+	}{
+		{name: "Threshold", mode: "", input: map[string]interface{}{"age": 20, "tier": "premium"}, expectedOutcome: "low", expectedExecuted: 4},
+		{name: "All pass", mode: OutcomeAllPass, input: map[string]interface{}{"age": 20, "tier": "premium"}, expectedOutcome: OutcomePass, expectedExecuted: 4},
+		{name: "All pass with a failure", mode: OutcomeAllPass, input: map[string]interface{}{"age": 16, "tier": "premium"}, expectedOutcome: OutcomeFail, expectedExecuted: 4},
+		{name: "Any pass", mode: OutcomeAnyPass, input: map[string]interface{}{"age": 16, "tier": ""}, expectedOutcome: OutcomePass, expectedExecuted: 4},
+		{name: "First", mode: OutcomeFirst, input: map[string]interface{}{"age": 16, "tier": "premium"}, expectedOutcome: "premium_queue", expectedExecuted: 2},
+		{name: "First with rule name", mode: OutcomeFirst, input: map[string]interface{}{"age": 20, "tier": ""}, expectedOutcome: "Adult", expectedExecuted: 1},
+		{name: "Unique with many matches", mode: OutcomeUnique, input: map[string]interface{}{"age": 20, "tier": ""}, expectError: true},
+		{name: "Priority", mode: OutcomePriority, input: map[string]interface{}{"age": 20, "tier": "premium"}, expectedOutcome: "priority_queue", expectedExecuted: 4},
+		{
+			name:             "Collect",
+			mode:             OutcomeCollect,
+			input:            map[string]interface{}{"age": 16, "tier": "gold"},
+			expectedOutcomes: []string{"priority_queue", "standard_queue"},
+			expectedExecuted: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NoError(t, CheckOutcomeMode(tt.mode))
+			policy := Policy{
+				ID:          "routing",
+				Rules:       rules,
+				OutcomeMode: tt.mode,
+				Thresholds:  []Threshold{{ID: "low", Value: 0}, {ID: "high", Value: 30}},
+			}
+
+			result, err := policy.Execute(MapInput(tt.input))
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedOutcome, result.Outcome)
+			assert.Equal(t, tt.expectedOutcomes, result.Outcomes)
+
+			executed := 0
+			for _, r := range result.Rules {
+				if r.Executed {
+					executed++
+				}
+			}
+			assert.Equal(t, tt.expectedExecuted, executed)
+		})
+	}
+
+	t.Run("Unique", func(t *testing.T) {
+		policy := Policy{ID: "routing", Rules: rules[:2], OutcomeMode: OutcomeUnique}
+		result, err := policy.Execute(MapInput(map[string]interface{}{"age": 16, "tier": "premium"}))
+		assert.NoError(t, err)
+		assert.Equal(t, "premium_queue", result.Outcome)
+
+		result, err = policy.Execute(MapInput(map[string]interface{}{"age": 16, "tier": ""}))
+		assert.NoError(t, err)
+		assert.Equal(t, "", result.Outcome)
+	})
+
+	assert.Error(t, CheckOutcomeMode("dmn"))
+}
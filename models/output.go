@@ -0,0 +1,42 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+)
+
+// Output is a named value computed by a policy after its rules are scored,
+// such as a limit or a review queue. The expression is evaluated with input,
+// score and rules, like the threshold conditions, and may return any value
+// that can be encoded in JSON.
+type Output struct {
+	Name            string
+	Expression      string
+	CompiledProgram cel.Program
+}
+
+// evaluateOutputs returns the values of the outputs by name, or nil if the
+// policy has no outputs.
+func (p *Policy) evaluateOutputs(vars map[string]interface{}) (map[string]interface{}, error) {
+	if len(p.Outputs) == 0 {
+		return nil, nil
+	}
+
+	values := make(map[string]interface{}, len(p.Outputs))
+	for _, output := range p.Outputs {
+		if output.CompiledProgram == nil {
+			return nil, fmt.Errorf("output %s is not compiled", output.Name)
+		}
+		out, _, err := output.CompiledProgram.Eval(vars)
+		if err != nil {
+			return nil, fmt.Errorf("error evaluating output %s: %v", output.Name, err)
+		}
+		value, err := nativeValue(out)
+		if err != nil {
+			return nil, fmt.Errorf("error evaluating output %s: %v", output.Name, err)
+		}
+		values[output.Name] = value
+	}
+	return values, nil
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sandrolain/rules/cel"
+	"github.com/sandrolain/rules/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy_Outputs(t *testing.T) {
+	env, err := cel.CreateRuleEnv()
+	assert.NoError(t, err)
+	policyEnv, err := cel.CreatePolicyEnv()
+	assert.NoError(t, err)
+	decisionEnv, err := cel.NewDecisionEnv(policyEnv)
+	assert.NoError(t, err)
+
+	rules := []Rule{
+		{Name: "Velocity", Expression: "input.velocity"},
+		{Name: "Sanctions", Expression: "input.country == 'XX'"},
+	}
+	for i := range rules {
+		assert.NoError(t, rules[i].BuildProgram(env))
+	}
+
+	outputs := []Output{
+		{Name: "limit", Expression: "score > 10.0 ? 100 : 500"},
+		{Name: "review_queue", Expression: "rules.Sanctions.passed ? 'high' : 'standard'"},
+		{Name: "reasons", Expression: "['velocity', 'sanctions'].filter(r, r == 'velocity' ? score > 10.0 : rules.Sanctions.passed)"},
+		{Name: "fee", Expression: "decimal('0.10') * decimal(3)"},
+		{Name: "checked_at", Expression: "timestamp('2024-05-01T10:00:00Z')"},
+		{Name: "details", Expression: "{'score': score, 'missing': null}"},
+	}
+	for i := range outputs {
+		outputs[i].CompiledProgram, err = utils.BuildExpression(decisionEnv, outputs[i].Expression, outputs[i].Name)
+		assert.NoError(t, err)
+	}
+	policy := Policy{ID: "limits", Rules: rules, Outputs: outputs}
+
+	result, err := policy.Execute(MapInput(map[string]interface{}{"velocity": 12.0, "country": "XX"}))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), result.Outputs["limit"])
+	assert.Equal(t, "high", result.Outputs["review_queue"])
+	assert.Equal(t, []interface{}{"velocity", "sanctions"}, result.Outputs["reasons"])
+	assert.Equal(t, "0.3", result.Outputs["fee"].(cel.Decimal).String())
+	assert.Equal(t, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), result.Outputs["checked_at"].(time.Time).UTC())
+	assert.Equal(t, map[string]interface{}{"score": 12.0, "missing": nil}, result.Outputs["details"])
+
+	t.Run("No outputs", func(t *testing.T) {
+		policy := Policy{ID: "limits", Rules: rules}
+		result, err := policy.Execute(MapInput(map[string]interface{}{"velocity": 12.0, "country": "XX"}))
+		assert.NoError(t, err)
+		assert.Nil(t, result.Outputs)
+	})
+
+	t.Run("Evaluation error", func(t *testing.T) {
+		policy := Policy{ID: "limits", Rules: rules, Outputs: outputs[:1]}
+		policy.Outputs = append(policy.Outputs, Output{Name: "ratio", Expression: "1 / 0"})
+		policy.Outputs[1].CompiledProgram, err = utils.BuildExpression(decisionEnv, "1 / 0", "ratio")
+		assert.NoError(t, err)
+		_, err := policy.Execute(MapInput(map[string]interface{}{"velocity": 12.0, "country": "XX"}))
+		assert.Error(t, err)
+	})
+}
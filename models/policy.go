@@ -2,37 +2,78 @@ package models
 
 import (
 	"fmt"
-	"sort"
+	"time"
 
 	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types/ref"
 	"github.com/sandrolain/rules/utils"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
-type Threshold struct {
-	ID    string
-	Value int64 // Cambiato da int a int64
-}
-
 type Policy struct {
-	ID              string
-	Name            string
-	Expression      string
-	Rules           []Rule
-	Thresholds      []Threshold
+	ID          string
+	Revision    int64
+	Name        string
+	InputSchema InputSchema
+	Expression  string
+	Rules       []Rule
+	Thresholds  []Threshold
+	Geofences   []Geofence
+	Aggregation Aggregation
+	OutcomeMode OutcomeMode
+	// ThresholdDirection tells whether higher or lower scores are worse
+	ThresholdDirection ThresholdDirection
+	// Outputs are evaluated, in order, after the threshold is selected
+	Outputs []Output
+	// MaxReasons limits the reasons of the results, if it is not zero
+	MaxReasons int
+	// Explain makes the engine explain every evaluation of the policy
+	Explain bool
+	// LibraryVersion is the version of the function library available to
+	// the expressions, or 0 for the latest one
+	LibraryVersion  uint32
 	CompiledProgram cel.Program
+	// Explainer explains the policy expression; it is set by the engine
+	Explainer *Explainer
+	// InputType is the message type built from InputSchema when the policy
+	// is compiled, or nil if the input is untyped
+	InputType protoreflect.MessageDescriptor
+	// InputTypeSchema is the input schema InputType was built from, which
+	// differs from InputSchema if the schema was edited after the compilation
+	InputTypeSchema InputSchema
 }
 
 func (p *Policy) ShouldExecute(input map[string]interface{}) (bool, error) {
+	return p.ShouldExecuteInput(MapInput(input))
+}
+
+func (p *Policy) ShouldExecuteInput(input Input) (bool, error) {
 	if p.CompiledProgram == nil {
 		return true, nil // If there's no expression, always execute the policy
 	}
 
-	out, _, err := p.CompiledProgram.Eval(map[string]interface{}{"input": input})
+	activation, err := p.activation(input)
 	if err != nil {
 		return false, err
 	}
+	return p.shouldExecute(activation)
+}
 
-	return out.Value().(bool), nil
+func (p *Policy) shouldExecute(activation map[string]interface{}) (bool, error) {
+	out, _, err := p.CompiledProgram.Eval(activation)
+	if err != nil {
+		return false, err
+	}
+	return gateValue(out)
+}
+
+// gateValue returns the value of the policy expression, which must be a bool.
+func gateValue(out ref.Val) (bool, error) {
+	execute, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("policy expression returned %s instead of bool", out.Type().TypeName())
+	}
+	return execute, nil
 }
 
 func (p *Policy) AddRule(env *cel.Env, rule Rule) error {
@@ -44,49 +85,241 @@ func (p *Policy) AddRule(env *cel.Env, rule Rule) error {
 
 		rule.CompiledProgram = program
 	}
+	if rule.When != "" && rule.CompiledWhen == nil {
+		program, err := utils.BuildExpression(env, rule.When, rule.Name, cel.BoolType)
+		if err != nil {
+			return err
+		}
+
+		rule.CompiledWhen = program
+	}
 
 	p.Rules = append(p.Rules, rule)
 	return nil
 }
 
 func (p *Policy) Evaluate(input map[string]interface{}) (string, []RuleResult, error) {
-	var totalScore int64
-	ruleResults := make([]RuleResult, len(p.Rules))
-	stopped := false
+	return p.EvaluateInput(MapInput(input))
+}
+
+func (p *Policy) EvaluateInput(input Input) (string, []RuleResult, error) {
+	result, err := p.Execute(input)
+	if err != nil {
+		return "", nil, err
+	}
+	return result.Threshold, result.Rules, nil
+}
+
+// PolicyResult is the result of the evaluation of the rules of a policy:
+// the highest threshold reached, the aggregated score, the outcome decided by
+// the outcome mode and the result of each rule, in the order of the rules.
+// Outcomes lists the outcomes of the collect mode, Actions the effects of the
+// actions of the threshold, Outputs the values of the outputs by name and
+// Reasons the ranked reasons of the rules.
+type PolicyResult struct {
+	Threshold string
+	Score     float64
+	Outcome   string
+	Outcomes  []string
+	Actions   ActionResults
+	Outputs   map[string]interface{}
+	Reasons   []Reason
+	Rules     []RuleResult
+	// Trace is set by ExecuteWithTrace
+	Trace *Trace
+}
+
+// Execute evaluates the rules of the policy, in order, until one of them
+// stops the evaluation, aggregates their scores, decides the outcome,
+// executes the actions of the threshold and evaluates the outputs. In
+// the first outcome mode the first passing rule also stops the evaluation.
+// The groups of rules are evaluated as single rules. It does not evaluate the
+// policy expression.
+func (p *Policy) Execute(input Input) (PolicyResult, error) {
+	activation, err := p.activation(input)
+	if err != nil {
+		return PolicyResult{}, err
+	}
+	return p.execute(activation, nil)
+}
+
+// Run evaluates the policy expression and, if it is true, executes the
+// policy like Execute. The expression is evaluated once, with the same input
+// as the rules; executed is false if it was false.
+func (p *Policy) Run(input Input) (result PolicyResult, executed bool, err error) {
+	activation, err := p.activation(input)
+	if err != nil {
+		return PolicyResult{}, false, err
+	}
+	if p.CompiledProgram != nil {
+		execute, err := p.shouldExecute(activation)
+		if err != nil {
+			return PolicyResult{}, false, fmt.Errorf("error evaluating policy expression: %v", err)
+		}
+		if !execute {
+			return PolicyResult{}, false, nil
+		}
+	}
+	result, err = p.execute(activation, nil)
+	if err != nil {
+		return PolicyResult{}, false, err
+	}
+	return result, true, nil
+}
 
-	for i, rule := range p.Rules {
-		if stopped {
-			ruleResults[i] = RuleResult{Executed: false}
+// execute evaluates the rules with the activation, recording their
+// evaluation in trace if it is not nil.
+func (p *Policy) execute(activation map[string]interface{}, trace *Trace) (PolicyResult, error) {
+	var traces *[]RuleTrace
+	if trace != nil {
+		traces = &trace.Rules
+	}
+	ruleResults, _, err := p.evaluateRules(p.Rules, activation, p.OutcomeMode == OutcomeFirst, traces)
+	if err != nil {
+		return PolicyResult{}, err
+	}
+
+	score, err := p.aggregate(activation, ruleResults)
+	if err != nil {
+		return PolicyResult{}, err
+	}
+	vars := p.decisionVars(activation, score, ruleResults)
+	threshold, err := p.selectThreshold(score, vars)
+	if err != nil {
+		return PolicyResult{}, err
+	}
+	if trace != nil {
+		if trace.Thresholds, err = p.traceThresholds(score, vars); err != nil {
+			return PolicyResult{}, err
+		}
+	}
+	actions, err := p.runActions(threshold, score, vars)
+	if err != nil {
+		return PolicyResult{}, err
+	}
+	outputs, err := p.evaluateOutputs(vars)
+	if err != nil {
+		return PolicyResult{}, err
+	}
+	outcome, outcomes, err := p.outcome(threshold, ruleResults)
+	if err != nil {
+		return PolicyResult{}, err
+	}
+	return PolicyResult{
+		Threshold: threshold,
+		Score:     score,
+		Outcome:   outcome,
+		Outcomes:  outcomes,
+		Actions:   actions,
+		Outputs:   outputs,
+		Reasons:   p.reasons(ruleResults),
+		Rules:     ruleResults,
+	}, nil
+}
+
+// evaluateRules evaluates rules, in order, until one of them stops the
+// evaluation, and returns their results and the name of the rule that
+// stopped. If stopOnPass is set, the first passing rule also stops. The
+// evaluation of each rule is appended to traces if it is not nil.
+func (p *Policy) evaluateRules(rules []Rule, activation map[string]interface{}, stopOnPass bool, traces *[]RuleTrace) ([]RuleResult, string, error) {
+	results := make([]RuleResult, len(rules))
+	stoppedBy := ""
+
+	for i, rule := range rules {
+		if stoppedBy != "" || rule.Disabled {
+			results[i] = RuleResult{Executed: false}
+			if traces != nil {
+				ruleTrace := RuleTrace{Name: rule.Name, Status: RuleSkipped, StoppedBy: stoppedBy}
+				if rule.Disabled {
+					ruleTrace = RuleTrace{Name: rule.Name, Status: RuleDisabled}
+				}
+				*traces = append(*traces, ruleTrace)
+			}
 			continue
 		}
 
-		result, err := rule.Evaluate(input)
+		start := time.Now()
+		applies, err := rule.applies(activation)
 		if err != nil {
-			return "", nil, fmt.Errorf("error evaluating rule %s: %v", rule.Name, err)
+			return nil, "", fmt.Errorf("error evaluating when expression of rule %s: %v", rule.Name, err)
+		}
+		if !applies {
+			results[i] = RuleResult{NotApplicable: true}
+			if traces != nil {
+				*traces = append(*traces, RuleTrace{Name: rule.Name, Status: RuleNotApplicable, Duration: time.Since(start)})
+			}
+			continue
+		}
+
+		var ruleTrace *RuleTrace
+		if traces != nil {
+			ruleTrace = &RuleTrace{Name: rule.Name, Status: RuleExecuted}
+		}
+		var result RuleResult
+		if rule.Group != nil {
+			result, err = p.evaluateGroup(rule, activation, ruleTrace)
+		} else {
+			result, err = rule.evaluate(activation)
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("error evaluating rule %s: %v", rule.Name, err)
+		}
+		if ruleTrace != nil {
+			ruleTrace.Duration = time.Since(start)
+			if rule.Explainer != nil {
+				ruleTrace.Expression = rule.Explainer.Explain(activation)
+			}
+			*traces = append(*traces, *ruleTrace)
 		}
 
-		result.Executed = true
-		ruleResults[i] = result
-		totalScore += result.Score
+		// A group is not applicable if none of its rules was executed
+		result.Executed = !result.NotApplicable
+		results[i] = result
 
-		if result.Stop {
-			stopped = true
+		if result.Stop || (stopOnPass && result.Passed) {
+			stoppedBy = rule.Name
 		}
 	}
-
-	return p.getThresholdID(totalScore), ruleResults, nil
+	return results, stoppedBy, nil
 }
 
-func (p *Policy) getThresholdID(score int64) string { // Cambiato da int a int64
-	sort.Slice(p.Thresholds, func(i, j int) bool {
-		return p.Thresholds[i].Value < p.Thresholds[j].Value
-	})
-
-	for i := len(p.Thresholds) - 1; i >= 0; i-- {
-		if score >= p.Thresholds[i].Value {
-			return p.Thresholds[i].ID
+// decisionVars returns the variables the threshold conditions, the publish
+// expressions and the outputs are evaluated with, or nil if the policy has
+// none of them.
+func (p *Policy) decisionVars(activation map[string]interface{}, score float64, results []RuleResult) map[string]interface{} {
+	hasExpressions := len(p.Outputs) > 0
+	for _, t := range p.Thresholds {
+		hasExpressions = hasExpressions || t.Condition != ""
+		for _, action := range t.Actions {
+			hasExpressions = hasExpressions || action.Expression != ""
 		}
 	}
+	if !hasExpressions {
+		return nil
+	}
 
-	return ""
+	rules := make(map[string]interface{}, len(results))
+	for i, result := range results {
+		// As for the rule operations, the first of the rules with the same
+		// name is the one addressed
+		if _, exists := rules[p.Rules[i].Name]; exists {
+			continue
+		}
+		vars := map[string]interface{}{
+			"score":    result.Score,
+			"passed":   result.Passed,
+			"executed": result.Executed,
+			"stop":     result.Stop,
+		}
+		// The groups also report their threshold
+		if p.Rules[i].Group != nil {
+			vars["threshold"] = result.Threshold
+		}
+		rules[p.Rules[i].Name] = vars
+	}
+	return map[string]interface{}{
+		"input": activation["input"],
+		"score": score,
+		"rules": rules,
+	}
 }
@@ -0,0 +1,196 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrRuleNotFound      = errors.New("rule not found")
+	ErrRuleExists        = errors.New("rule already exists")
+	ErrThresholdNotFound = errors.New("threshold not found")
+)
+
+// Clone returns a copy of the policy that can be edited without affecting
+// the original. Compiled programs are shared, since they are immutable.
+func (p Policy) Clone() Policy {
+	p.Rules = cloneRules(p.Rules)
+	p.Thresholds = append([]Threshold(nil), p.Thresholds...)
+	for i := range p.Thresholds {
+		p.Thresholds[i].Actions = append([]Action(nil), p.Thresholds[i].Actions...)
+	}
+	p.Geofences = append([]Geofence(nil), p.Geofences...)
+	p.Outputs = append([]Output(nil), p.Outputs...)
+	return p
+}
+
+func (p *Policy) ruleIndex(name string) int {
+	for i, rule := range p.Rules {
+		if rule.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// findRule returns the index of the named rule. Only the top-level rules can
+// be edited: a rule of a group is not found, and is changed by updating the
+// rule of its group.
+func (p *Policy) findRule(name string) (int, error) {
+	if i := p.ruleIndex(name); i >= 0 {
+		return i, nil
+	}
+	if group := groupPath(p.Rules, name); group != "" {
+		return -1, fmt.Errorf("%w: %s is a rule of group %s; only top-level rules can be edited, update the group instead", ErrRuleNotFound, name, group)
+	}
+	return -1, fmt.Errorf("%w: %s", ErrRuleNotFound, name)
+}
+
+// groupPath returns the path of the group containing the named rule, or an
+// empty string if no group contains it.
+func groupPath(rules []Rule, name string) string {
+	for _, rule := range rules {
+		if rule.Group == nil {
+			continue
+		}
+		for _, child := range rule.Group.Rules {
+			if child.Name == name {
+				return rule.Name
+			}
+		}
+		if path := groupPath(rule.Group.Rules, name); path != "" {
+			return rule.Name + "/" + path
+		}
+	}
+	return ""
+}
+
+func (p *Policy) thresholdIndex(id string) int {
+	for i, threshold := range p.Thresholds {
+		if threshold.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// InsertRule adds a top-level rule at the given position, or at the end of
+// the rules if position is negative. The rule is compiled when the policy is
+// set.
+func (p *Policy) InsertRule(rule Rule, position int) error {
+	if rule.Name == "" {
+		return fmt.Errorf("rule name cannot be empty")
+	}
+	if p.ruleIndex(rule.Name) >= 0 {
+		return fmt.Errorf("%w: %s", ErrRuleExists, rule.Name)
+	}
+	if position < 0 || position > len(p.Rules) {
+		position = len(p.Rules)
+	}
+
+	p.Rules = append(p.Rules, Rule{})
+	copy(p.Rules[position+1:], p.Rules[position:])
+	p.Rules[position] = rule
+	return nil
+}
+
+// UpdateRule replaces the named top-level rule, keeping its position. The
+// compiled programs are kept if their expressions do not change.
+func (p *Policy) UpdateRule(name string, rule Rule) error {
+	i, err := p.findRule(name)
+	if err != nil {
+		return err
+	}
+	if rule.Name == "" {
+		rule.Name = name
+	}
+	if rule.Name != name && p.ruleIndex(rule.Name) >= 0 {
+		return fmt.Errorf("%w: %s", ErrRuleExists, rule.Name)
+	}
+	if rule.CompiledProgram == nil && rule.Expression == p.Rules[i].Expression {
+		rule.CompiledProgram = p.Rules[i].CompiledProgram
+		rule.Explainer = p.Rules[i].Explainer
+	}
+	if rule.CompiledWhen == nil && rule.When == p.Rules[i].When {
+		rule.CompiledWhen = p.Rules[i].CompiledWhen
+	}
+	p.Rules[i] = rule
+	return nil
+}
+
+func (p *Policy) RemoveRule(name string) error {
+	i, err := p.findRule(name)
+	if err != nil {
+		return err
+	}
+	p.Rules = append(p.Rules[:i], p.Rules[i+1:]...)
+	return nil
+}
+
+// ReorderRules sorts the rules in the given order. The names must be exactly
+// the names of the top-level rules of the policy.
+func (p *Policy) ReorderRules(names []string) error {
+	if len(names) != len(p.Rules) {
+		return fmt.Errorf("expected %d rule names, got %d", len(p.Rules), len(names))
+	}
+
+	rules := make([]Rule, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		i, err := p.findRule(name)
+		if err != nil {
+			return err
+		}
+		if seen[name] {
+			return fmt.Errorf("rule %s listed more than once", name)
+		}
+		seen[name] = true
+		rules = append(rules, p.Rules[i])
+	}
+	p.Rules = rules
+	return nil
+}
+
+// SetRuleEnabled enables or disables a rule. A disabled rule is not
+// evaluated and is reported as not executed.
+func (p *Policy) SetRuleEnabled(name string, enabled bool) error {
+	i, err := p.findRule(name)
+	if err != nil {
+		return err
+	}
+	p.Rules[i].Disabled = !enabled
+	return nil
+}
+
+// SetThreshold adds a threshold, or replaces the one with the same ID.
+func (p *Policy) SetThreshold(threshold Threshold) error {
+	if threshold.ID == "" {
+		return fmt.Errorf("threshold ID cannot be empty")
+	}
+	if i := p.thresholdIndex(threshold.ID); i >= 0 {
+		p.Thresholds[i] = threshold
+		return nil
+	}
+	p.Thresholds = append(p.Thresholds, threshold)
+	return nil
+}
+
+func (p *Policy) RemoveThreshold(id string) error {
+	i := p.thresholdIndex(id)
+	if i < 0 {
+		return fmt.Errorf("%w: %s", ErrThresholdNotFound, id)
+	}
+	p.Thresholds = append(p.Thresholds[:i], p.Thresholds[i+1:]...)
+	return nil
+}
+
+// SetThresholdEnabled enables or disables a threshold. A disabled threshold
+// is never selected.
+func (p *Policy) SetThresholdEnabled(id string, enabled bool) error {
+	i := p.thresholdIndex(id)
+	if i < 0 {
+		return fmt.Errorf("%w: %s", ErrThresholdNotFound, id)
+	}
+	p.Thresholds[i].Disabled = !enabled
+	return nil
+}
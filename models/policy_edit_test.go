@@ -0,0 +1,196 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/cel-go/cel"
+	rcel "github.com/sandrolain/rules/cel"
+	"github.com/sandrolain/rules/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func newEditPolicy() Policy {
+	return Policy{
+		ID:   "test_policy",
+		Name: "TestPolicy",
+		Rules: []Rule{
+			{Name: "Rule1", Expression: "Result(1, false)"},
+			{Name: "Rule2", Expression: "Result(2, false)"},
+			{Name: "Rule3", Expression: "Result(3, false)"},
+		},
+		Thresholds: []Threshold{
+			{ID: "low", Value: 0},
+			{ID: "high", Value: 10},
+		},
+	}
+}
+
+func ruleNames(p Policy) []string {
+	names := make([]string, len(p.Rules))
+	for i, rule := range p.Rules {
+		names[i] = rule.Name
+	}
+	return names
+}
+
+func TestPolicy_Clone(t *testing.T) {
+	policy := newEditPolicy()
+	clone := policy.Clone()
+	assert.NoError(t, clone.RemoveRule("Rule1"))
+	assert.NoError(t, clone.SetThresholdEnabled("low", false))
+
+	assert.Len(t, policy.Rules, 3)
+	assert.False(t, policy.Thresholds[0].Disabled)
+}
+
+func TestPolicy_InsertRule(t *testing.T) {
+	tests := []struct {
+		name        string
+		rule        Rule
+		position    int
+		expected    []string
+		expectError bool
+	}{
+		{"Append", Rule{Name: "Rule4"}, -1, []string{"Rule1", "Rule2", "Rule3", "Rule4"}, false},
+		{"Insert at start", Rule{Name: "Rule4"}, 0, []string{"Rule4", "Rule1", "Rule2", "Rule3"}, false},
+		{"Insert in the middle", Rule{Name: "Rule4"}, 2, []string{"Rule1", "Rule2", "Rule4", "Rule3"}, false},
+		{"Position past the end", Rule{Name: "Rule4"}, 10, []string{"Rule1", "Rule2", "Rule3", "Rule4"}, false},
+		{"Duplicate name", Rule{Name: "Rule2"}, -1, nil, true},
+		{"Empty name", Rule{}, -1, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := newEditPolicy()
+			err := policy.InsertRule(tt.rule, tt.position)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, ruleNames(policy))
+			}
+		})
+	}
+}
+
+func TestPolicy_UpdateRule(t *testing.T) {
+	policy := newEditPolicy()
+	program := mustCompileProgram(t, "true")
+	policy.Rules[1].CompiledProgram = program
+
+	t.Run("Same expression keeps the program", func(t *testing.T) {
+		assert.NoError(t, policy.UpdateRule("Rule2", Rule{Expression: "Result(2, false)", Disabled: true}))
+		assert.Equal(t, "Rule2", policy.Rules[1].Name)
+		assert.True(t, policy.Rules[1].Disabled)
+		assert.True(t, policy.Rules[1].CompiledProgram == program)
+	})
+
+	t.Run("New expression drops the program", func(t *testing.T) {
+		assert.NoError(t, policy.UpdateRule("Rule2", Rule{Name: "Renamed", Expression: "Result(5, false)"}))
+		assert.Equal(t, []string{"Rule1", "Renamed", "Rule3"}, ruleNames(policy))
+		assert.Nil(t, policy.Rules[1].CompiledProgram)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		assert.ErrorIs(t, policy.UpdateRule("Missing", Rule{}), ErrRuleNotFound)
+		assert.ErrorIs(t, policy.UpdateRule("Rule1", Rule{Name: "Rule3"}), ErrRuleExists)
+	})
+}
+
+func TestPolicy_RemoveRule(t *testing.T) {
+	policy := newEditPolicy()
+	assert.NoError(t, policy.RemoveRule("Rule2"))
+	assert.Equal(t, []string{"Rule1", "Rule3"}, ruleNames(policy))
+	assert.ErrorIs(t, policy.RemoveRule("Rule2"), ErrRuleNotFound)
+}
+
+func TestPolicy_EditGroupedRule(t *testing.T) {
+	policy := newEditPolicy()
+	policy.Rules = append(policy.Rules, Rule{Name: "Identity", Group: &RuleGroup{Rules: []Rule{
+		{Name: "Document", Expression: "1"},
+		{Name: "Checks", Group: &RuleGroup{Rules: []Rule{{Name: "Sanctions", Expression: "2"}}}},
+	}}})
+
+	err := policy.SetRuleEnabled("Sanctions", false)
+	assert.ErrorIs(t, err, ErrRuleNotFound)
+	assert.ErrorContains(t, err, "group Identity/Checks")
+	assert.ErrorIs(t, policy.UpdateRule("Document", Rule{Expression: "3"}), ErrRuleNotFound)
+	assert.ErrorIs(t, policy.RemoveRule("Document"), ErrRuleNotFound)
+	assert.False(t, policy.Rules[3].Group.Rules[1].Group.Rules[0].Disabled)
+
+	// The group rule itself is a top-level rule
+	assert.NoError(t, policy.SetRuleEnabled("Identity", false))
+}
+
+func TestPolicy_ReorderRules(t *testing.T) {
+	tests := []struct {
+		name        string
+		order       []string
+		expectError bool
+	}{
+		{"Valid order", []string{"Rule3", "Rule1", "Rule2"}, false},
+		{"Missing rule", []string{"Rule3", "Rule1"}, true},
+		{"Unknown rule", []string{"Rule3", "Rule1", "Rule4"}, true},
+		{"Duplicate rule", []string{"Rule3", "Rule1", "Rule1"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := newEditPolicy()
+			err := policy.ReorderRules(tt.order)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Equal(t, []string{"Rule1", "Rule2", "Rule3"}, ruleNames(policy))
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.order, ruleNames(policy))
+			}
+		})
+	}
+}
+
+func TestPolicy_Thresholds(t *testing.T) {
+	policy := newEditPolicy()
+
+	assert.NoError(t, policy.SetThreshold(Threshold{ID: "medium", Value: 5}))
+	assert.NoError(t, policy.SetThreshold(Threshold{ID: "high", Value: 20}))
+	assert.Len(t, policy.Thresholds, 3)
+	assert.Equal(t, float64(20), policy.Thresholds[1].Value)
+	assert.Error(t, policy.SetThreshold(Threshold{}))
+
+	assert.NoError(t, policy.SetThresholdEnabled("medium", false))
+	assert.Equal(t, "low", policy.getThresholdID(7))
+	assert.NoError(t, policy.SetThresholdEnabled("medium", true))
+	assert.Equal(t, "medium", policy.getThresholdID(7))
+	assert.ErrorIs(t, policy.SetThresholdEnabled("missing", true), ErrThresholdNotFound)
+
+	assert.NoError(t, policy.RemoveThreshold("medium"))
+	assert.Len(t, policy.Thresholds, 2)
+	assert.ErrorIs(t, policy.RemoveThreshold("medium"), ErrThresholdNotFound)
+}
+
+func TestPolicy_DisabledRule(t *testing.T) {
+	policy := newEditPolicy()
+	for i := range policy.Rules {
+		policy.Rules[i].CompiledProgram = mustCompileRuleProgram(t, policy.Rules[i].Expression)
+	}
+	assert.NoError(t, policy.SetRuleEnabled("Rule3", false))
+	assert.Error(t, policy.SetRuleEnabled("Missing", false))
+
+	threshold, results, err := policy.Evaluate(map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Equal(t, "low", threshold)
+	assert.Equal(t, RuleResult{}, results[2])
+	assert.Equal(t, float64(2), results[1].Score)
+}
+
+func mustCompileRuleProgram(t *testing.T, expression string) cel.Program {
+	t.Helper()
+	env, err := rcel.CreateRuleEnv()
+	assert.NoError(t, err)
+
+	program, err := utils.BuildExpression(env, expression, "test")
+	assert.NoError(t, err)
+
+	return program
+}
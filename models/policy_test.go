@@ -5,6 +5,9 @@ import (
 
 	"github.com/google/cel-go/cel"
 	"github.com/google/cel-go/checker/decls"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	rcel "github.com/sandrolain/rules/cel"
 	"github.com/sandrolain/rules/utils"
 	"github.com/stretchr/testify/assert"
 )
@@ -94,6 +97,17 @@ func TestPolicy_ShouldExecute(t *testing.T) {
 			expectedResult: false,
 			expectError:    true,
 		},
+		{
+			name: "Policy with non-bool dyn result",
+			policy: Policy{
+				Name:            "DynExpression",
+				Expression:      "input.key",
+				CompiledProgram: mustCompileProgram(t, "input.key"),
+			},
+			input:          map[string]interface{}{"key": "execute"},
+			expectedResult: false,
+			expectError:    true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -143,6 +157,15 @@ func TestPolicy_AddRule(t *testing.T) {
 			},
 			expectError: true,
 		},
+		{
+			name: "Add rule with non-boolean when expression",
+			rule: Rule{
+				Name:       "InvalidWhen",
+				Expression: "input.value > 10",
+				When:       "input.country + 'x'",
+			},
+			expectError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -165,6 +188,63 @@ func TestPolicy_AddRule(t *testing.T) {
 	}
 }
 
+func TestPolicy_RuleWhen(t *testing.T) {
+	env, err := rcel.CreateRuleEnv()
+	assert.NoError(t, err)
+
+	policy := Policy{ID: "payments"}
+	for _, rule := range []Rule{
+		{Name: "Domestic", Expression: "10", When: "input.country == 'IT'"},
+		{Name: "Foreign", Expression: "30", When: "input.country != 'IT'"},
+		{Name: "Stop", Expression: "Result(5, true)", When: "input.amount > 1000"},
+		{Name: "Amount", Expression: "input.amount / 100"},
+	} {
+		assert.NoError(t, policy.AddRule(env, rule))
+	}
+
+	result, err := policy.Execute(MapInput(map[string]interface{}{"country": "FR", "amount": 500}))
+	assert.NoError(t, err)
+	assert.Equal(t, 35.0, result.Score)
+	assert.Equal(t, RuleResult{NotApplicable: true}, result.Rules[0])
+	assert.True(t, result.Rules[1].Executed)
+	// A rule that is not applicable does not stop the evaluation
+	assert.Equal(t, RuleResult{NotApplicable: true}, result.Rules[2])
+	assert.True(t, result.Rules[3].Executed)
+
+	result, err = policy.ExecuteWithTrace(MapInput(map[string]interface{}{"country": "IT", "amount": 500}))
+	assert.NoError(t, err)
+	assert.Equal(t, 15.0, result.Score)
+	assert.Equal(t, RuleNotApplicable, result.Trace.Rules[1].Status)
+
+	_, err = policy.Execute(MapInput(map[string]interface{}{"amount": 500}))
+	assert.Error(t, err)
+}
+
+func TestPolicy_Run(t *testing.T) {
+	calls := int64(0)
+	env, err := rcel.NewPolicyEnv(nil, cel.Function("calls",
+		cel.Overload("calls", nil, cel.IntType, cel.FunctionBinding(func(...ref.Val) ref.Val {
+			calls++
+			return types.Int(calls)
+		})),
+	))
+	assert.NoError(t, err)
+
+	program, err := utils.BuildExpression(env, "calls() == 1", "gate")
+	assert.NoError(t, err)
+	policy := Policy{ID: "payments", CompiledProgram: program}
+
+	_, executed, err := policy.Run(MapInput(nil))
+	assert.NoError(t, err)
+	assert.True(t, executed)
+	assert.Equal(t, int64(1), calls)
+
+	_, executed, err = policy.Run(MapInput(nil))
+	assert.NoError(t, err)
+	assert.False(t, executed)
+	assert.Equal(t, int64(2), calls)
+}
+
 func mustCompileProgram(t *testing.T, expression string) cel.Program {
 	t.Helper()
 	env, err := cel.NewEnv(cel.Declarations(
@@ -0,0 +1,66 @@
+package models
+
+import "sort"
+
+// Reason is a reason reported by a rule, ranked among the reasons of the
+// policy by the contribution of the rule to the score.
+type Reason struct {
+	Code    string
+	Message string
+	Rule    string
+	Score   float64
+}
+
+// reasons returns the reasons of the executed rules, including the rules of
+// the groups, from the rule that contributed most to a worse score: the
+// highest score, or the lowest in the descending threshold direction. A code
+// is reported once, with the message of its top rule, and at most MaxReasons
+// reasons are returned if it is set.
+func (p *Policy) reasons(results []RuleResult) []Reason {
+	reasons := ruleReasons(p.Rules, results, nil)
+
+	descending := p.ThresholdDirection == ThresholdDescending
+	sort.SliceStable(reasons, func(i, j int) bool {
+		if descending {
+			return reasons[i].Score < reasons[j].Score
+		}
+		return reasons[i].Score > reasons[j].Score
+	})
+
+	ranked := reasons[:0]
+	codes := make(map[string]bool, len(reasons))
+	for _, reason := range reasons {
+		if codes[reason.Code] {
+			continue
+		}
+		codes[reason.Code] = true
+		ranked = append(ranked, reason)
+		if p.MaxReasons > 0 && len(ranked) == p.MaxReasons {
+			break
+		}
+	}
+	return ranked
+}
+
+// ruleReasons appends the reasons of the executed rules and of their groups
+// to reasons.
+func ruleReasons(rules []Rule, results []RuleResult, reasons []Reason) []Reason {
+	for i, result := range results {
+		if !result.Executed {
+			continue
+		}
+		if rules[i].Group != nil {
+			reasons = ruleReasons(rules[i].Group.Rules, result.Rules, reasons)
+		}
+		if result.ReasonCode == "" {
+			continue
+		}
+		reasons = append(reasons, Reason{
+			Code:    result.ReasonCode,
+			Message: result.Message,
+			Rule:    rules[i].Name,
+			Score:   result.Score,
+		})
+	}
+	return reasons
+}
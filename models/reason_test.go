@@ -0,0 +1,75 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/sandrolain/rules/cel"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy_Reasons(t *testing.T) {
+	env, err := cel.CreateRuleEnv()
+	assert.NoError(t, err)
+
+	rules := []Rule{
+		{Name: "Income", Expression: "Result(input.income < 30000 ? -40 : 0, false, 'INCOME_LOW', 'Income below 30000')"},
+		{Name: "Debt", Expression: "Result(input.dti > 0.4 ? -80 : 0, false, 'DTI_HIGH', 'Debt to income ratio above 0.4')"},
+		{Name: "Delinquency", Expression: "Result(input.late_payments * -20, false, 'DELINQUENT')"},
+		{Name: "Age", Expression: "input.age >= 18"},
+		{Name: "DebtTrend", Expression: "Result(-10, false, 'DTI_HIGH', 'Debt growing')"},
+	}
+	for i := range rules {
+		assert.NoError(t, rules[i].BuildProgram(env))
+	}
+	input := MapInput(map[string]interface{}{"income": 20000, "dti": 0.5, "late_payments": 1, "age": 30})
+
+	tests := []struct {
+		name       string
+		direction  ThresholdDirection
+		maxReasons int
+		expected   []Reason
+	}{
+		{
+			name:      "Lower is worse",
+			direction: ThresholdDescending,
+			expected: []Reason{
+				{Code: "DTI_HIGH", Message: "Debt to income ratio above 0.4", Rule: "Debt", Score: -80},
+				{Code: "INCOME_LOW", Message: "Income below 30000", Rule: "Income", Score: -40},
+				{Code: "DELINQUENT", Rule: "Delinquency", Score: -20},
+			},
+		},
+		{
+			name:       "Limited",
+			direction:  ThresholdDescending,
+			maxReasons: 2,
+			expected: []Reason{
+				{Code: "DTI_HIGH", Message: "Debt to income ratio above 0.4", Rule: "Debt", Score: -80},
+				{Code: "INCOME_LOW", Message: "Income below 30000", Rule: "Income", Score: -40},
+			},
+		},
+		{
+			name: "Higher is worse",
+			expected: []Reason{
+				{Code: "DTI_HIGH", Message: "Debt growing", Rule: "DebtTrend", Score: -10},
+				{Code: "DELINQUENT", Rule: "Delinquency", Score: -20},
+				{Code: "INCOME_LOW", Message: "Income below 30000", Rule: "Income", Score: -40},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := Policy{ID: "credit", Rules: rules, ThresholdDirection: tt.direction, MaxReasons: tt.maxReasons}
+			result, err := policy.Execute(input)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result.Reasons)
+		})
+	}
+
+	t.Run("No reasons", func(t *testing.T) {
+		policy := Policy{ID: "credit", Rules: rules[3:4]}
+		result, err := policy.Execute(input)
+		assert.NoError(t, err)
+		assert.Empty(t, result.Reasons)
+	})
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// PolicyRevision is a numbered version of a policy definition. Revisions of
+// a policy are numbered from 1, in the order they were set.
+type PolicyRevision struct {
+	Revision  int64
+	Policy    Policy
+	Timestamp time.Time
+	Author    string
+	Note      string
+}
@@ -2,15 +2,87 @@ package models
 
 import (
 	"fmt"
+	"math"
+	"math/big"
 
 	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types/ref"
 	"github.com/sandrolain/rules/utils"
 )
 
 type Rule struct {
-	Name            string
-	Expression      string
+	Name       string
+	Expression string
+	Disabled   bool
+	// The score returned by the expression is multiplied by Multiplier,
+	// limited to the range from MinScore to MaxScore, then multiplied by
+	// Weight. Nil fields leave the score as it is.
+	Weight     *float64
+	Multiplier *float64
+	MinScore   *float64
+	MaxScore   *float64
+	// Outcome is the outcome of the policy when the rule decides it in the
+	// hit policy outcome modes. It is the name of the rule if empty.
+	Outcome string
+	// Priority decides between the passing rules in the priority outcome
+	// mode: the outcome is the one of the rule with the highest priority.
+	Priority int64
+	// When is a boolean expression over the input: if it is set and false,
+	// the rule is not applicable and is not executed
+	When string
+	// Group makes the rule a group of rules; the rule has no expression then
+	Group           *RuleGroup
 	CompiledProgram cel.Program
+	CompiledWhen    cel.Program
+	// Explainer explains the expression; it is set by the engine
+	Explainer *Explainer
+}
+
+// outcome returns the outcome reported when the rule matches.
+func (r *Rule) outcome() string {
+	if r.Outcome == "" {
+		return r.Name
+	}
+	return r.Outcome
+}
+
+// CheckScore checks the score fields of the rule.
+func (r *Rule) CheckScore() error {
+	for _, f := range []*float64{r.Weight, r.Multiplier, r.MinScore, r.MaxScore} {
+		if f != nil && (math.IsNaN(*f) || math.IsInf(*f, 0)) {
+			return fmt.Errorf("rule %s has a score field that is not a finite number", r.Name)
+		}
+	}
+	if r.MinScore != nil && r.MaxScore != nil && *r.MinScore > *r.MaxScore {
+		return fmt.Errorf("rule %s has a minimum score greater than its maximum score", r.Name)
+	}
+	return nil
+}
+
+// weight returns the weight of the rule, which is 1 if it has none.
+func (r *Rule) weight() float64 {
+	if r.Weight == nil {
+		return 1
+	}
+	return *r.Weight
+}
+
+// score applies the multiplier, the limits and the weight of the rule to the
+// score returned by its expression.
+func (r *Rule) score(value float64) float64 {
+	if r.Multiplier != nil {
+		value *= *r.Multiplier
+	}
+	if r.MinScore != nil {
+		value = math.Max(value, *r.MinScore)
+	}
+	if r.MaxScore != nil {
+		value = math.Min(value, *r.MaxScore)
+	}
+	if r.Weight != nil {
+		value *= *r.Weight
+	}
+	return value
 }
 
 func (r *Rule) BuildProgram(env *cel.Env) error {
@@ -22,14 +94,34 @@ func (r *Rule) BuildProgram(env *cel.Env) error {
 	return nil
 }
 
+// applies evaluates the when expression of the rule, if it has one.
+func (r *Rule) applies(activation map[string]interface{}) (bool, error) {
+	if r.CompiledWhen == nil {
+		return true, nil
+	}
+	out, _, err := r.CompiledWhen.Eval(activation)
+	if err != nil {
+		return false, err
+	}
+	applies, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("when expression returned %s instead of bool", out.Type().TypeName())
+	}
+	return applies, nil
+}
+
 func (r *Rule) Evaluate(input map[string]interface{}) (RuleResult, error) {
+	return r.evaluate(map[string]interface{}{
+		"input": input,
+	})
+}
+
+func (r *Rule) evaluate(activation map[string]interface{}) (RuleResult, error) {
 	if r.CompiledProgram == nil {
 		return RuleResult{}, fmt.Errorf("compiled program is nil")
 	}
 
-	out, _, err := r.CompiledProgram.Eval(map[string]interface{}{
-		"input": input,
-	})
+	out, _, err := r.CompiledProgram.Eval(activation)
 	if err != nil {
 		return RuleResult{}, err
 	}
@@ -37,14 +129,22 @@ func (r *Rule) Evaluate(input map[string]interface{}) (RuleResult, error) {
 	switch value := out.Value().(type) {
 	case int64:
 		return RuleResult{
-			Score:    value,
+			Score:    r.score(float64(value)),
 			Stop:     false,
 			Passed:   true,
 			Executed: true,
 		}, nil
 	case float64:
 		return RuleResult{
-			Score:    int64(value),
+			Score:    r.score(value),
+			Stop:     false,
+			Passed:   true,
+			Executed: true,
+		}, nil
+	case *big.Rat:
+		score, _ := value.Float64()
+		return RuleResult{
+			Score:    r.score(score),
 			Stop:     false,
 			Passed:   true,
 			Executed: true,
@@ -57,29 +157,53 @@ func (r *Rule) Evaluate(input map[string]interface{}) (RuleResult, error) {
 			Executed: true,
 		}, nil
 	case map[string]interface{}:
-		score, ok := value["value"].(int64)
-		if !ok {
-			if floatScore, ok := value["value"].(float64); ok {
-				score = int64(floatScore)
-			} else {
-				return RuleResult{}, fmt.Errorf("invalid score value")
-			}
+		var score float64
+		switch v := value["value"].(type) {
+		case int64:
+			score = float64(v)
+		case float64:
+			score = v
+		case *big.Rat:
+			score, _ = v.Float64()
+		default:
+			return RuleResult{}, fmt.Errorf("invalid score value")
 		}
 		stop, _ := value["stop"].(bool)
-		return RuleResult{
-			Score:    score,
+		result := RuleResult{
+			Score:    r.score(score),
 			Stop:     stop,
 			Passed:   true,
 			Executed: true,
-		}, nil
+		}
+		result.ReasonCode, _ = value["reason"].(string)
+		result.Message, _ = value["message"].(string)
+		if metadata, ok := value["metadata"].(ref.Val); ok {
+			values, err := nativeValue(metadata)
+			if err != nil {
+				return RuleResult{}, fmt.Errorf("invalid metadata: %v", err)
+			}
+			result.Metadata, _ = values.(map[string]interface{})
+		}
+		return result, nil
 	default:
-		return RuleResult{}, fmt.Errorf("unsupported result type")
+		return RuleResult{}, fmt.Errorf("unsupported result type %s", out.Type().TypeName())
 	}
 }
 
+// RuleResult is the result of a rule. The reason code, the message and the
+// metadata are the ones returned with Result. NotApplicable is true if the
+// rule was not executed because its when expression was false. The results
+// of a group also report the threshold of the group and the results of its
+// rules, in the order of the rules.
 type RuleResult struct {
-	Score    int64
-	Stop     bool
-	Passed   bool
-	Executed bool
+	Score         float64
+	Stop          bool
+	Passed        bool
+	Executed      bool
+	NotApplicable bool
+	ReasonCode    string
+	Message       string
+	Metadata      map[string]interface{}
+	Threshold     string
+	Rules         []RuleResult
 }
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"math"
 	"testing"
 
 	"github.com/sandrolain/rules/cel"
@@ -78,6 +79,100 @@ func TestRule_Evaluate(t *testing.T) {
 			expectedResult: RuleResult{Score: 30, Stop: true, Passed: true, Executed: true},
 			expectError:    false,
 		},
+		{
+			name: "Decimal score",
+			rule: Rule{
+				Name:       "DecimalRule",
+				Expression: "decimal('0.1') * decimal(25)",
+			},
+			input:          map[string]interface{}{},
+			expectedResult: RuleResult{Score: 2.5, Stop: false, Passed: true, Executed: true},
+			expectError:    false,
+		},
+		{
+			name: "Decimal result",
+			rule: Rule{
+				Name:       "DecimalResultRule",
+				Expression: "Result(-decimal('7.5'), true)",
+			},
+			input:          map[string]interface{}{},
+			expectedResult: RuleResult{Score: -7.5, Stop: true, Passed: true, Executed: true},
+			expectError:    false,
+		},
+		{
+			name: "Reason code",
+			rule: Rule{
+				Name:       "ReasonRule",
+				Expression: "Result(5, false, 'INCOME_LOW')",
+			},
+			input:          map[string]interface{}{},
+			expectedResult: RuleResult{Score: 5, Passed: true, Executed: true, ReasonCode: "INCOME_LOW"},
+			expectError:    false,
+		},
+		{
+			name: "Reason message and metadata",
+			rule: Rule{
+				Name:       "DebtRule",
+				Expression: "Result(20, false, 'DTI_HIGH', 'Debt to income ratio %.2f above %.2f'.format([double(input.dti), 0.4]), {'dti': input.dti, 'product': 'mortgage'})",
+			},
+			input: map[string]interface{}{"dti": 0.55},
+			expectedResult: RuleResult{
+				Score:      20,
+				Passed:     true,
+				Executed:   true,
+				ReasonCode: "DTI_HIGH",
+				Message:    "Debt to income ratio 0.55 above 0.40",
+				Metadata:   map[string]interface{}{"dti": 0.55, "product": "mortgage"},
+			},
+			expectError: false,
+		},
+		{
+			name: "Fractional score",
+			rule: Rule{
+				Name:       "ProbabilityRule",
+				Expression: "input.probability",
+			},
+			input:          map[string]interface{}{"probability": 0.25},
+			expectedResult: RuleResult{Score: 0.25, Stop: false, Passed: true, Executed: true},
+			expectError:    false,
+		},
+		{
+			name: "Multiplier and weight",
+			rule: Rule{
+				Name:       "WeightedRule",
+				Expression: "Result(input.probability, false)",
+				Multiplier: ptr(1000.0),
+				Weight:     ptr(0.5),
+			},
+			input:          map[string]interface{}{"probability": 0.25},
+			expectedResult: RuleResult{Score: 125, Stop: false, Passed: true, Executed: true},
+			expectError:    false,
+		},
+		{
+			name: "Clamped score",
+			rule: Rule{
+				Name:       "ClampedRule",
+				Expression: "input.value * 10",
+				MinScore:   ptr(0.0),
+				MaxScore:   ptr(100.0),
+				Weight:     ptr(2.0),
+			},
+			input:          map[string]interface{}{"value": 15},
+			expectedResult: RuleResult{Score: 200, Stop: false, Passed: true, Executed: true},
+			expectError:    false,
+		},
+		{
+			name: "Boolean result ignores the score fields",
+			rule: Rule{
+				Name:       "BoolRule",
+				Expression: "input.value > 10",
+				MinScore:   ptr(5.0),
+				Weight:     ptr(2.0),
+			},
+			input:          map[string]interface{}{"value": 15},
+			expectedResult: RuleResult{Score: 0, Stop: false, Passed: true, Executed: true},
+			expectError:    false,
+		},
 		{
 			name: "Invalid rule",
 			rule: Rule{
@@ -108,3 +203,15 @@ func TestRule_Evaluate(t *testing.T) {
 		})
 	}
 }
+
+func TestRule_CheckScore(t *testing.T) {
+	assert.NoError(t, (&Rule{Name: "r"}).CheckScore())
+	assert.NoError(t, (&Rule{Name: "r", MinScore: ptr(0.0), MaxScore: ptr(0.0)}).CheckScore())
+	assert.Error(t, (&Rule{Name: "r", MinScore: ptr(1.0), MaxScore: ptr(0.0)}).CheckScore())
+	assert.Error(t, (&Rule{Name: "r", Weight: ptr(math.Inf(1))}).CheckScore())
+	assert.Error(t, (&Rule{Name: "r", Multiplier: ptr(math.NaN())}).CheckScore())
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
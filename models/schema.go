@@ -0,0 +1,13 @@
+package models
+
+// InputSchema declares the type of the input of a policy, either as a JSON
+// Schema document or as the full name of a protobuf message from a
+// registered descriptor set. The zero value leaves the input untyped.
+type InputSchema struct {
+	JSONSchema  string
+	MessageType string
+}
+
+func (s InputSchema) IsZero() bool {
+	return s == InputSchema{}
+}
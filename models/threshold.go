@@ -0,0 +1,228 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/google/cel-go/cel"
+)
+
+// ThresholdDirection tells which end of the score scale is the worse one.
+type ThresholdDirection string
+
+const (
+	// ThresholdAscending makes higher scores worse. It is the direction of
+	// the policies without one.
+	ThresholdAscending ThresholdDirection = "ascending"
+	// ThresholdDescending makes lower scores worse.
+	ThresholdDescending ThresholdDirection = "descending"
+)
+
+// CheckThresholdDirection checks that a direction is one of the threshold
+// directions.
+func CheckThresholdDirection(direction ThresholdDirection) error {
+	switch direction {
+	case "", ThresholdAscending, ThresholdDescending:
+		return nil
+	}
+	return fmt.Errorf("unknown threshold direction %q", direction)
+}
+
+// ScoreRange is a range of scores. A nil bound leaves the range unbounded on
+// its side, and the bounds are included unless they are exclusive.
+type ScoreRange struct {
+	Min          *float64
+	Max          *float64
+	MinExclusive bool
+	MaxExclusive bool
+}
+
+func (r *ScoreRange) Contains(score float64) bool {
+	if r.Min != nil && (score < *r.Min || (r.MinExclusive && score == *r.Min)) {
+		return false
+	}
+	if r.Max != nil && (score > *r.Max || (r.MaxExclusive && score == *r.Max)) {
+		return false
+	}
+	return true
+}
+
+func (r *ScoreRange) check() error {
+	for _, bound := range []*float64{r.Min, r.Max} {
+		if bound != nil && (math.IsNaN(*bound) || math.IsInf(*bound, 0)) {
+			return fmt.Errorf("range bounds must be finite numbers")
+		}
+	}
+	if r.Min != nil && r.Max != nil {
+		if *r.Min > *r.Max || (*r.Min == *r.Max && (r.MinExclusive || r.MaxExclusive)) {
+			return fmt.Errorf("range is empty")
+		}
+	}
+	return nil
+}
+
+// Threshold is a named level of the score of a policy. A threshold is
+// reached by the scores from Value up, or down to Value in the descending
+// direction, or by the scores in Range if it has one. A threshold with a
+// Condition is reached when the condition is true instead, and its Value or
+// Range only rank it among the other thresholds.
+//
+// The condition is a CEL expression evaluated with input, score, the score of
+// the policy, and rules, a map from the rule names to the results of the
+// rules, each a map with the score, passed, executed and stop keys. For
+// example score > 50.0 || rules.sanctions.passed.
+type Threshold struct {
+	ID              string
+	Value           float64
+	Range           *ScoreRange
+	Condition       string
+	Disabled        bool
+	CompiledProgram cel.Program
+	// Actions are executed, in order, when the threshold is selected
+	Actions []Action
+}
+
+// Position returns where the threshold starts on the score scale: its Value,
+// or the lower bound of its Range. The thresholds are ranked by position,
+// and the most severe of the ones reached is selected.
+func (t *Threshold) Position() float64 {
+	if t.Range == nil {
+		return t.Value
+	}
+	if t.Range.Min == nil {
+		return math.Inf(-1)
+	}
+	return *t.Range.Min
+}
+
+func (t *Threshold) reachedBy(score float64, direction ThresholdDirection) bool {
+	switch {
+	case t.Range != nil:
+		return t.Range.Contains(score)
+	case direction == ThresholdDescending:
+		return score <= t.Value
+	default:
+		return score >= t.Value
+	}
+}
+
+// CheckThresholds checks that the IDs of the thresholds are unique, and the
+// ranges of the enabled thresholds. The ones without a condition must either
+// all have a range or none, and their ranges must neither overlap nor leave
+// gaps between them.
+func CheckThresholds(thresholds []Threshold) error {
+	var ranged []*Threshold
+	valued := 0
+	ids := make(map[string]bool, len(thresholds))
+	for i := range thresholds {
+		t := &thresholds[i]
+		if ids[t.ID] {
+			return fmt.Errorf("duplicate threshold ID %s", t.ID)
+		}
+		ids[t.ID] = true
+		if t.Range != nil {
+			if err := t.Range.check(); err != nil {
+				return fmt.Errorf("threshold %s: %v", t.ID, err)
+			}
+		}
+		if t.Disabled || t.Condition != "" {
+			continue
+		}
+		if t.Range != nil {
+			ranged = append(ranged, t)
+		} else {
+			valued++
+		}
+	}
+	if len(ranged) > 0 && valued > 0 {
+		return fmt.Errorf("thresholds without a condition must either all have a range or none")
+	}
+
+	sort.SliceStable(ranged, func(i, j int) bool {
+		return ranged[i].Position() < ranged[j].Position()
+	})
+	for i := 1; i < len(ranged); i++ {
+		prev, next := ranged[i-1], ranged[i]
+		if prev.Range.Max == nil || next.Range.Min == nil {
+			return fmt.Errorf("thresholds %s and %s overlap", prev.ID, next.ID)
+		}
+		upper, lower := *prev.Range.Max, *next.Range.Min
+		switch {
+		case upper > lower || (upper == lower && !prev.Range.MaxExclusive && !next.Range.MinExclusive):
+			return fmt.Errorf("thresholds %s and %s overlap", prev.ID, next.ID)
+		case upper < lower || (upper == lower && prev.Range.MaxExclusive && next.Range.MinExclusive):
+			return fmt.Errorf("thresholds %s and %s leave a gap between them", prev.ID, next.ID)
+		}
+	}
+	return nil
+}
+
+// getThresholdID returns the most severe threshold reached by the score,
+// skipping the thresholds with a condition.
+func (p *Policy) getThresholdID(score float64) string {
+	id, _ := p.selectThreshold(score, nil)
+	return id
+}
+
+// selectThreshold returns the most severe of the enabled thresholds reached:
+// the one with the highest position, or the lowest in the descending
+// direction, and the first of them if their positions are equal. The
+// conditions are evaluated with vars, and skipped if vars is nil. It does not
+// reorder p.Thresholds, since policies are shared between goroutines.
+func (p *Policy) selectThreshold(score float64, vars map[string]interface{}) (string, error) {
+	return selectThreshold(p.Thresholds, p.ThresholdDirection, score, vars)
+}
+
+// selectThreshold selects one of thresholds as for the thresholds of a
+// policy.
+func selectThreshold(thresholds []Threshold, direction ThresholdDirection, score float64, vars map[string]interface{}) (string, error) {
+	descending := direction == ThresholdDescending
+	var best *Threshold
+	for i := range thresholds {
+		t := &thresholds[i]
+		if t.Disabled {
+			continue
+		}
+
+		reached := t.reachedBy(score, direction)
+		if t.Condition != "" {
+			if vars == nil {
+				continue
+			}
+			var err error
+			if reached, err = t.evaluateCondition(vars); err != nil {
+				return "", err
+			}
+		}
+		if !reached {
+			continue
+		}
+
+		if best == nil ||
+			(!descending && t.Position() > best.Position()) ||
+			(descending && t.Position() < best.Position()) {
+			best = t
+		}
+	}
+
+	if best == nil {
+		return "", nil
+	}
+	return best.ID, nil
+}
+
+func (t *Threshold) evaluateCondition(vars map[string]interface{}) (bool, error) {
+	if t.CompiledProgram == nil {
+		return false, fmt.Errorf("condition of threshold %s is not compiled", t.ID)
+	}
+	out, _, err := t.CompiledProgram.Eval(vars)
+	if err != nil {
+		return false, fmt.Errorf("error evaluating condition of threshold %s: %v", t.ID, err)
+	}
+	reached, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("condition of threshold %s returned %s instead of bool", t.ID, out.Type().TypeName())
+	}
+	return reached, nil
+}
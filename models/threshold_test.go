@@ -0,0 +1,197 @@
+package models
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sandrolain/rules/cel"
+	"github.com/sandrolain/rules/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScoreRange_Contains(t *testing.T) {
+	tests := []struct {
+		name     string
+		r        ScoreRange
+		score    float64
+		expected bool
+	}{
+		{"Inside", ScoreRange{Min: ptr(0.0), Max: ptr(10.0)}, 5, true},
+		{"Inclusive min", ScoreRange{Min: ptr(0.0), Max: ptr(10.0)}, 0, true},
+		{"Inclusive max", ScoreRange{Min: ptr(0.0), Max: ptr(10.0)}, 10, true},
+		{"Exclusive min", ScoreRange{Min: ptr(0.0), MinExclusive: true}, 0, false},
+		{"Exclusive max", ScoreRange{Max: ptr(10.0), MaxExclusive: true}, 10, false},
+		{"Below", ScoreRange{Min: ptr(0.0)}, -1, false},
+		{"Unbounded", ScoreRange{}, math.MaxFloat64, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.r.Contains(tt.score))
+		})
+	}
+}
+
+func TestCheckThresholds(t *testing.T) {
+	tests := []struct {
+		name        string
+		thresholds  []Threshold
+		expectError bool
+	}{
+		{"Values", []Threshold{{ID: "low", Value: 0}, {ID: "high", Value: 10}}, false},
+		{"Duplicate IDs", []Threshold{{ID: "low", Value: 0}, {ID: "low", Value: 10}}, true},
+		{
+			"Adjacent ranges",
+			[]Threshold{
+				{ID: "high", Range: &ScoreRange{Min: ptr(10.0)}},
+				{ID: "low", Range: &ScoreRange{Max: ptr(10.0), MaxExclusive: true}},
+			},
+			false,
+		},
+		{
+			"Overlapping ranges",
+			[]Threshold{
+				{ID: "low", Range: &ScoreRange{Max: ptr(10.0)}},
+				{ID: "high", Range: &ScoreRange{Min: ptr(10.0)}},
+			},
+			true,
+		},
+		{
+			"Gap between ranges",
+			[]Threshold{
+				{ID: "low", Range: &ScoreRange{Max: ptr(5.0)}},
+				{ID: "high", Range: &ScoreRange{Min: ptr(10.0)}},
+			},
+			true,
+		},
+		{
+			"Gap at an excluded point",
+			[]Threshold{
+				{ID: "low", Range: &ScoreRange{Max: ptr(10.0), MaxExclusive: true}},
+				{ID: "high", Range: &ScoreRange{Min: ptr(10.0), MinExclusive: true}},
+			},
+			true,
+		},
+		{
+			"Two unbounded ranges",
+			[]Threshold{
+				{ID: "low", Range: &ScoreRange{Max: ptr(5.0)}},
+				{ID: "all", Range: &ScoreRange{}},
+			},
+			true,
+		},
+		{
+			"Disabled and conditional thresholds are not checked",
+			[]Threshold{
+				{ID: "low", Range: &ScoreRange{Max: ptr(5.0)}},
+				{ID: "high", Range: &ScoreRange{Min: ptr(10.0)}, Disabled: true},
+				{ID: "flagged", Range: &ScoreRange{Min: ptr(1.0)}, Condition: "true"},
+			},
+			false,
+		},
+		{"Mixed values and ranges", []Threshold{{ID: "low", Value: 0}, {ID: "high", Range: &ScoreRange{Min: ptr(10.0)}}}, true},
+		{"Empty range", []Threshold{{ID: "high", Range: &ScoreRange{Min: ptr(10.0), Max: ptr(10.0), MaxExclusive: true}}}, true},
+		{"Infinite bound", []Threshold{{ID: "high", Range: &ScoreRange{Min: ptr(math.Inf(1))}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckThresholds(tt.thresholds)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPolicy_SelectThreshold(t *testing.T) {
+	ranges := []Threshold{
+		{ID: "low", Range: &ScoreRange{Max: ptr(10.0), MaxExclusive: true}},
+		{ID: "medium", Range: &ScoreRange{Min: ptr(10.0), Max: ptr(50.0), MaxExclusive: true}},
+		{ID: "high", Range: &ScoreRange{Min: ptr(50.0)}},
+	}
+	values := []Threshold{
+		{ID: "good", Value: 700},
+		{ID: "fair", Value: 600},
+		{ID: "poor", Value: 0},
+	}
+
+	tests := []struct {
+		name       string
+		thresholds []Threshold
+		direction  ThresholdDirection
+		score      float64
+		expected   string
+	}{
+		{"Range below", ranges, "", -5, "low"},
+		{"Range lower bound", ranges, "", 10, "medium"},
+		{"Range upper bound", ranges, "", 50, "high"},
+		{"Ascending", values, ThresholdAscending, 650, "fair"},
+		{"Descending", values, ThresholdDescending, 650, "good"},
+		{"Descending at the bound", values, ThresholdDescending, 600, "fair"},
+		{"Descending worst", values, ThresholdDescending, -1, "poor"},
+		{"Descending none", values, ThresholdDescending, 800, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := Policy{Thresholds: tt.thresholds, ThresholdDirection: tt.direction}
+			assert.Equal(t, tt.expected, policy.getThresholdID(tt.score))
+		})
+	}
+}
+
+func TestPolicy_ThresholdConditions(t *testing.T) {
+	env, err := cel.CreateRuleEnv()
+	assert.NoError(t, err)
+	policyEnv, err := cel.CreatePolicyEnv()
+	assert.NoError(t, err)
+	decisionEnv, err := cel.NewDecisionEnv(policyEnv)
+	assert.NoError(t, err)
+
+	rules := []Rule{
+		{Name: "Amount", Expression: "input.amount / 100.0"},
+		{Name: "Sanctions", Expression: "input.country == 'XX'"},
+	}
+	for i := range rules {
+		assert.NoError(t, rules[i].BuildProgram(env))
+	}
+	thresholds := []Threshold{
+		{ID: "review", Value: 10},
+		{ID: "block", Value: 50, Condition: "score >= 50.0 || rules.Sanctions.passed"},
+	}
+	for i := range thresholds {
+		if thresholds[i].Condition != "" {
+			thresholds[i].CompiledProgram, err = utils.BuildExpression(decisionEnv, thresholds[i].Condition, thresholds[i].ID)
+			assert.NoError(t, err)
+		}
+	}
+	policy := Policy{ID: "payments", Rules: rules, Thresholds: thresholds}
+
+	tests := []struct {
+		name     string
+		input    map[string]interface{}
+		expected string
+	}{
+		{"Below", map[string]interface{}{"amount": 500.0, "country": "IT"}, ""},
+		{"Score", map[string]interface{}{"amount": 1500.0, "country": "IT"}, "review"},
+		{"Condition on the score", map[string]interface{}{"amount": 6000.0, "country": "IT"}, "block"},
+		{"Condition on a rule", map[string]interface{}{"amount": 500.0, "country": "XX"}, "block"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := policy.Execute(MapInput(tt.input))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result.Threshold)
+		})
+	}
+
+	t.Run("Not compiled", func(t *testing.T) {
+		policy := Policy{ID: "payments", Rules: rules, Thresholds: []Threshold{{ID: "block", Condition: "true"}}}
+		_, err := policy.Execute(MapInput(map[string]interface{}{"amount": 1.0, "country": "IT"}))
+		assert.Error(t, err)
+	})
+}
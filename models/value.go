@@ -0,0 +1,72 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+
+	rcel "github.com/sandrolain/rules/cel"
+
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// nativeValue converts a value returned by an expression to the Go value
+// that encodes it in JSON: nil, a bool, an int64, a uint64, a float64, a
+// string, a time, a decimal, a list of values or a map of values with string
+// keys. Protobuf messages are converted through their JSON encoding.
+func nativeValue(val ref.Val) (interface{}, error) {
+	switch v := val.(type) {
+	case types.Null:
+		return nil, nil
+	case types.Bool, types.Int, types.Uint, types.Double, types.String, types.Bytes:
+		return v.Value(), nil
+	case types.Timestamp:
+		return v.Time, nil
+	case types.Duration:
+		return v.Duration.String(), nil
+	case rcel.Decimal:
+		return v, nil
+	case traits.Mapper:
+		values := make(map[string]interface{})
+		for it := v.Iterator(); it.HasNext() == types.True; {
+			key := it.Next()
+			name, ok := key.Value().(string)
+			if !ok {
+				return nil, fmt.Errorf("map key %v is not a string", key.Value())
+			}
+			value, err := nativeValue(v.Get(key))
+			if err != nil {
+				return nil, err
+			}
+			values[name] = value
+		}
+		return values, nil
+	case traits.Lister:
+		size, _ := v.Size().(types.Int)
+		values := make([]interface{}, size)
+		for i := range values {
+			value, err := nativeValue(v.Get(types.Int(i)))
+			if err != nil {
+				return nil, err
+			}
+			values[i] = value
+		}
+		return values, nil
+	}
+
+	if msg, ok := val.Value().(proto.Message); ok {
+		data, err := protojson.Marshal(msg)
+		if err != nil {
+			return nil, err
+		}
+		var value interface{}
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	}
+	return nil, fmt.Errorf("unsupported value type %s", val.Type().TypeName())
+}
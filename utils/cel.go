@@ -2,21 +2,131 @@ package utils
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
 )
 
-// BuildExpression compiles a CEL expression and returns a Program
-func BuildExpression(env *cel.Env, expression string, name string) (cel.Program, error) {
+// BuildExpression compiles a CEL expression and returns a Program.
+//
+// If allowed types are given, the expression is rejected unless its checked
+// output type is one of them. Expressions typed dyn are accepted, since their
+// type is known only when they are evaluated.
+func BuildExpression(env *cel.Env, expression string, name string, allowed ...*cel.Type) (cel.Program, error) {
+	_, program, err := CompileExpression(env, expression, name, allowed...)
+	return program, err
+}
+
+// CompileExpression is like BuildExpression, and also returns the checked
+// AST of the expression.
+func CompileExpression(env *cel.Env, expression string, name string, allowed ...*cel.Type) (*cel.Ast, cel.Program, error) {
 	ast, iss := env.Compile(expression)
 	if iss.Err() != nil {
-		return nil, fmt.Errorf("error compiling expression %s: %v", name, iss.Err())
+		return nil, nil, fmt.Errorf("error compiling expression %s: %v", name, iss.Err())
+	}
+
+	if err := CheckOutputType(ast.OutputType(), allowed...); err != nil {
+		return nil, nil, fmt.Errorf("error checking expression %s: %v", name, err)
 	}
 
 	program, err := env.Program(ast)
 	if err != nil {
-		return nil, fmt.Errorf("error creating program for %s: %v", name, err)
+		return nil, nil, fmt.Errorf("error creating program for %s: %v", name, err)
+	}
+
+	return ast, program, nil
+}
+
+// CheckOutputType returns an error if t is not one of the allowed types. Any
+// type is accepted when allowed is empty, and dyn is always accepted.
+func CheckOutputType(t *cel.Type, allowed ...*cel.Type) error {
+	if len(allowed) == 0 || isDynType(t) {
+		return nil
+	}
+	names := make([]string, len(allowed))
+	for i, a := range allowed {
+		if t.IsExactType(a) {
+			return nil
+		}
+		names[i] = a.String()
+	}
+	return fmt.Errorf("expression returns %s, expected %s", t, strings.Join(names, ", "))
+}
+
+// isDynType reports whether t is known only at evaluation time. Values read
+// from the input map are typed as google.protobuf.Any, which is handled as
+// dyn.
+func isDynType(t *cel.Type) bool {
+	return t.Kind() == types.DynKind || t.Kind() == types.AnyKind
+}
+
+// Issue is a problem found compiling an expression. Line and Column are
+// 1-based positions in the expression source, or 0 when the position is
+// unknown.
+type Issue struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+// CompileIssues compiles a CEL expression and returns the problems found,
+// each with its position, instead of a single error. If allowed types are
+// given, an output type that is not one of them is reported at the start of
+// the expression, as in BuildExpression. So are the errors creating the
+// program, like invalid constant arguments parsed in advance.
+func CompileIssues(env *cel.Env, expression string, allowed ...*cel.Type) []Issue {
+	_, _, issues := CompileProgram(env, expression, allowed...)
+	return issues
+}
+
+// CompileProgram is like CompileIssues, and also returns the checked AST and
+// the program of the expression if there are no issues.
+func CompileProgram(env *cel.Env, expression string, allowed ...*cel.Type) (*cel.Ast, cel.Program, []Issue) {
+	ast, iss := env.Compile(expression)
+	if iss.Err() == nil {
+		if err := CheckOutputType(ast.OutputType(), allowed...); err != nil {
+			return nil, nil, []Issue{expressionIssue(ast, err)}
+		}
+		program, err := env.Program(ast)
+		if err != nil {
+			return nil, nil, []Issue{expressionIssue(ast, err)}
+		}
+		return ast, program, nil
 	}
 
-	return program, nil
+	errs := iss.Errors()
+	issues := make([]Issue, len(errs))
+	for i, e := range errs {
+		issues[i] = Issue{Message: e.Message}
+		if e.Location != nil && e.Location.Line() > 0 {
+			issues[i].Line = e.Location.Line()
+			// Columns are 0-based in CEL
+			issues[i].Column = e.Location.Column() + 1
+		}
+	}
+	return nil, nil, issues
+}
+
+// expressionIssue reports err at the start of the expression.
+func expressionIssue(ast *cel.Ast, err error) Issue {
+	issue := Issue{Message: err.Error()}
+	info := ast.NativeRep().SourceInfo()
+
+	// The offset of a call is the one of its operator, so the start of the
+	// expression is the lowest offset of any of its terms
+	start := int32(-1)
+	for _, r := range info.OffsetRanges() {
+		if start < 0 || r.Start < start {
+			start = r.Start
+		}
+	}
+	if start < 0 {
+		return issue
+	}
+	if loc := info.GetLocationByOffset(start); loc.Line() > 0 {
+		issue.Line = loc.Line()
+		issue.Column = loc.Column() + 1
+	}
+	return issue
 }
@@ -140,3 +140,79 @@ func TestBuildExpression_WithDifferentEnvs(t *testing.T) {
 		})
 	}
 }
+
+func TestCompileIssues(t *testing.T) {
+	env, err := cel.NewEnv(cel.Declarations(
+		decls.NewVar("input", decls.NewMapType(decls.String, decls.Any)),
+	))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		expression string
+		expected   []Issue
+	}{
+		{
+			name:       "Valid expression",
+			expression: "input.age > 18",
+			expected:   nil,
+		},
+		{
+			name:       "Undefined variable",
+			expression: "input.age > 18 &&\n  undefinedVar",
+			expected: []Issue{
+				{Line: 2, Column: 3, Message: "undeclared reference to 'undefinedVar' (in container '')"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, CompileIssues(env, tt.expression))
+		})
+	}
+
+	t.Run("Syntax error", func(t *testing.T) {
+		issues := CompileIssues(env, "input.age >")
+		assert.NotEmpty(t, issues)
+		assert.Equal(t, 1, issues[0].Line)
+		assert.Greater(t, issues[0].Column, 0)
+	})
+}
+
+func TestCheckOutputType(t *testing.T) {
+	env, err := cel.NewEnv(cel.Declarations(
+		decls.NewVar("input", decls.NewMapType(decls.String, decls.Any)),
+	))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		expression  string
+		allowed     []*cel.Type
+		expectError bool
+	}{
+		{"No allowed types", "'text'", nil, false},
+		{"Allowed type", "input.age > 18", []*cel.Type{cel.BoolType}, false},
+		{"Dyn type", "input.enabled", []*cel.Type{cel.BoolType}, false},
+		{"Wrong type", "input.age + 1", []*cel.Type{cel.BoolType}, true},
+		{"One of the allowed types", "1.5", []*cel.Type{cel.IntType, cel.DoubleType}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast, iss := env.Compile(tt.expression)
+			assert.NoError(t, iss.Err())
+
+			err := CheckOutputType(ast.OutputType(), tt.allowed...)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			_, err = BuildExpression(env, tt.expression, tt.name, tt.allowed...)
+			assert.Equal(t, tt.expectError, err != nil)
+		})
+	}
+}